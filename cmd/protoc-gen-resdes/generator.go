@@ -0,0 +1,356 @@
+package main
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	resdesv1 "github.com/signal426/resdes/proto/gen/resdes/v1"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const (
+	resdesPackage   = protogen.GoImportPath("github.com/signal426/resdes")
+	fieldMaskName   = protoreflect.FullName("google.protobuf.FieldMask")
+	updateMaskField = protoreflect.Name("update_mask")
+)
+
+// assertion is a single call on the generated validator. A ForEach assertion
+// holds the calls to make on the rules of every element
+type assertion struct {
+	method string
+	path   string
+	getter string
+	args   []any
+	each   []assertion
+}
+
+// block holds the assertions for the fields of a message in declaration order. Nested
+// blocks hold the assertions of a singular message field and only run if it is set
+type block struct {
+	getter     string
+	assertions []assertion
+	nested     []*block
+	// order of assertions and nested blocks, true for a nested block
+	order []bool
+}
+
+func (b *block) empty() bool {
+	return len(b.assertions) == 0 && len(b.nested) == 0
+}
+
+// generateFile writes the validator constructors for every message in the
+// file that has rules declared on it or on the messages it holds
+func generateFile(gen *protogen.Plugin, file *protogen.File) error {
+	type validator struct {
+		message *protogen.Message
+		mask    string
+		rules   *block
+	}
+	var validators []validator
+	for _, m := range flattenMessages(file.Messages) {
+		rules, err := collectRules(m, "", "msg", []*protogen.Message{m})
+		if err != nil {
+			return err
+		}
+		if rules.empty() {
+			continue
+		}
+		validators = append(validators, validator{
+			message: m,
+			mask:    maskGetter(m),
+			rules:   rules,
+		})
+	}
+	if len(validators) == 0 {
+		return nil
+	}
+
+	pkgName := file.GoPackageName + "resdes"
+	dir, base := path.Split(file.GeneratedFilenamePrefix)
+	g := gen.NewGeneratedFile(
+		path.Join(dir, string(pkgName), base+".resdes.go"),
+		protogen.GoImportPath(path.Join(string(file.GoImportPath), string(pkgName))),
+	)
+	g.P("// Code generated by protoc-gen-resdes. DO NOT EDIT.")
+	g.P("// source: ", file.Desc.Path())
+	g.P()
+	g.P("package ", pkgName)
+	for _, v := range validators {
+		validatorType := resdesPackage.Ident("DefaultMessageValidator")
+		g.P()
+		g.P("// New", v.message.GoIdent.GoName, "Validator returns a validator for the rules declared on ", v.message.Desc.FullName())
+		g.P("func New", v.message.GoIdent.GoName, "Validator(msg *", v.message.GoIdent, ") *", validatorType, "[*", v.message.GoIdent, "] {")
+		g.P("v := ", resdesPackage.Ident("ForMessage"), "[*", v.message.GoIdent, "](", v.mask, ")")
+		generateBlock(g, v.rules)
+		g.P("return v")
+		g.P("}")
+	}
+	return nil
+}
+
+func generateBlock(g *protogen.GeneratedFile, b *block) {
+	var a, n int
+	for _, nested := range b.order {
+		if nested {
+			g.P("if ", b.nested[n].getter, " != nil {")
+			generateBlock(g, b.nested[n])
+			g.P("}")
+			n++
+			continue
+		}
+		if b.assertions[a].each != nil {
+			generateEach(g, b.assertions[a])
+			a++
+			continue
+		}
+		call := []any{"v.", b.assertions[a].method, "(", strconv.Quote(b.assertions[a].path), ", ", b.assertions[a].getter}
+		for _, arg := range b.assertions[a].args {
+			call = append(call, ", ", arg)
+		}
+		g.P(append(call, ")")...)
+		a++
+	}
+}
+
+func generateEach(g *protogen.GeneratedFile, a assertion) {
+	g.P("v.ForEach(", strconv.Quote(a.path), ", func(each *", resdesPackage.Ident("EachRules"), ") {")
+	for _, e := range a.each {
+		call := []any{"each.", e.method, "(", strconv.Quote(e.path)}
+		for _, arg := range e.args {
+			call = append(call, ", ", arg)
+		}
+		g.P(append(call, ")")...)
+	}
+	g.P("})")
+}
+
+// collectRules returns the assertions for the rules declared on the fields of the message
+// and the messages it holds. stack guards against recursive messages
+func collectRules(m *protogen.Message, prefix string, getter string, stack []*protogen.Message) (*block, error) {
+	b := &block{getter: getter}
+	for _, field := range m.Fields {
+		fieldPath := prefix + string(field.Desc.Name())
+		fieldGetter := getter + ".Get" + field.GoName + "()"
+		rules, _ := proto.GetExtension(field.Desc.Options(), resdesv1.E_Field).(*resdesv1.FieldRules)
+		assertions, err := assertionsForRules(field, rules)
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range assertions {
+			a.path = fieldPath
+			a.getter = fieldGetter
+			b.assertions = append(b.assertions, a)
+			b.order = append(b.order, false)
+		}
+		if field.Desc.IsList() || field.Desc.IsMap() {
+			each, err := collectEachRules(field, stack)
+			if err != nil {
+				return nil, err
+			}
+			if len(each) > 0 {
+				b.assertions = append(b.assertions, assertion{method: "ForEach", path: fieldPath, each: each})
+				b.order = append(b.order, false)
+			}
+			continue
+		}
+		if field.Message == nil || onStack(field.Message, stack) {
+			continue
+		}
+		nested, err := collectRules(field.Message, fieldPath+".", fieldGetter, append(stack, field.Message))
+		if err != nil {
+			return nil, err
+		}
+		if !nested.empty() {
+			b.nested = append(b.nested, nested)
+			b.order = append(b.order, true)
+		}
+	}
+	return b, nil
+}
+
+// collectEachRules returns the assertions for the rules declared on the fields of the elements of a
+// repeated or map message field, as calls on EachRules. Rules on messages nested in the elements are
+// rejected, as ForEach cannot skip them when the nested message is unset
+func collectEachRules(field *protogen.Field, stack []*protogen.Message) ([]assertion, error) {
+	elem := field.Message
+	if field.Desc.IsMap() {
+		elem = field.Message.Fields[1].Message
+	}
+	if elem == nil || onStack(elem, stack) {
+		return nil, nil
+	}
+	var each []assertion
+	for _, f := range elem.Fields {
+		rules, _ := proto.GetExtension(f.Desc.Options(), resdesv1.E_Field).(*resdesv1.FieldRules)
+		assertions, err := assertionsForRules(f, rules)
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range assertions {
+			a.method = strings.Replace(strings.Replace(a.method, "AssertNonZero", "Require", 1), "Assert", "Require", 1)
+			a.path = string(f.Desc.Name())
+			each = append(each, a)
+		}
+		if f.Message == nil || onStack(f.Message, append(stack, elem)) {
+			continue
+		}
+		nested := &block{}
+		if f.Desc.IsList() || f.Desc.IsMap() {
+			nestedEach, err := collectEachRules(f, append(stack, elem))
+			if err != nil {
+				return nil, err
+			}
+			nested.assertions = nestedEach
+		} else if nested, err = collectRules(f.Message, "", "", append(stack, elem, f.Message)); err != nil {
+			return nil, err
+		}
+		if !nested.empty() {
+			return nil, fmt.Errorf("resdes: %s: rules of messages nested in the elements of %s are not supported", f.Desc.FullName(), field.Desc.FullName())
+		}
+	}
+	return each, nil
+}
+
+func assertionsForRules(field *protogen.Field, rules *resdesv1.FieldRules) ([]assertion, error) {
+	if rules == nil {
+		return nil, nil
+	}
+	var suffix string
+	if rules.GetInMaskOnly() {
+		suffix = "WhenInMask"
+	}
+	var assertions []assertion
+	if rules.GetRequired() {
+		assertions = append(assertions, assertion{method: "AssertNonZero" + suffix})
+	}
+	if rules.NotEqual != nil {
+		lit, err := literal(field, rules.GetNotEqual())
+		if err != nil {
+			return nil, err
+		}
+		assertions = append(assertions, assertion{method: "AssertNotEqualTo" + suffix, args: []any{lit}})
+	}
+	if rules.Equal != nil {
+		lit, err := literal(field, rules.GetEqual())
+		if err != nil {
+			return nil, err
+		}
+		assertions = append(assertions, assertion{method: "AssertEqualTo" + suffix, args: []any{lit}})
+	}
+	if rules.MinLen != nil || rules.MaxLen != nil {
+		if field.Desc.Kind() != protoreflect.StringKind || field.Desc.IsList() || field.Desc.IsMap() {
+			return nil, fmt.Errorf("resdes: %s: min_len and max_len only apply to string fields", field.Desc.FullName())
+		}
+	}
+	if rules.MinLen != nil {
+		assertions = append(assertions, assertion{method: "AssertMinLength" + suffix, args: []any{strconv.FormatUint(uint64(rules.GetMinLen()), 10)}})
+	}
+	if rules.MaxLen != nil {
+		assertions = append(assertions, assertion{method: "AssertMaxLength" + suffix, args: []any{strconv.FormatUint(uint64(rules.GetMaxLen()), 10)}})
+	}
+	return assertions, nil
+}
+
+// literal returns the Go expression for the value written in a rule, typed
+// as the value returned by the field's getter
+func literal(field *protogen.Field, value string) (any, error) {
+	fd := field.Desc
+	if fd.IsList() || fd.IsMap() {
+		return nil, fmt.Errorf("resdes: %s: equality rules do not apply to repeated or map fields", fd.FullName())
+	}
+	invalid := func(err error) error {
+		return fmt.Errorf("resdes: %s: invalid %s literal %q: %w", fd.FullName(), fd.Kind(), value, err)
+	}
+	switch fd.Kind() {
+	case protoreflect.StringKind:
+		return strconv.Quote(value), nil
+	case protoreflect.BytesKind:
+		return "[]byte(" + strconv.Quote(value) + ")", nil
+	case protoreflect.BoolKind:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, invalid(err)
+		}
+		return strconv.FormatBool(b), nil
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return integerLiteral("int32", value, 32, invalid)
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return integerLiteral("int64", value, 64, invalid)
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return unsignedLiteral("uint32", value, 32, invalid)
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return unsignedLiteral("uint64", value, 64, invalid)
+	case protoreflect.FloatKind:
+		return floatLiteral("float32", value, 32, invalid)
+	case protoreflect.DoubleKind:
+		return floatLiteral("float64", value, 64, invalid)
+	case protoreflect.EnumKind:
+		for _, ev := range field.Enum.Values {
+			if string(ev.Desc.Name()) == value {
+				return ev.GoIdent, nil
+			}
+		}
+		return nil, invalid(fmt.Errorf("not a value of %s", field.Enum.Desc.FullName()))
+	}
+	return nil, fmt.Errorf("resdes: %s: equality rules do not apply to %s fields", fd.FullName(), fd.Kind())
+}
+
+func integerLiteral(goType string, value string, bits int, invalid func(error) error) (any, error) {
+	i, err := strconv.ParseInt(value, 10, bits)
+	if err != nil {
+		return nil, invalid(err)
+	}
+	return goType + "(" + strconv.FormatInt(i, 10) + ")", nil
+}
+
+func unsignedLiteral(goType string, value string, bits int, invalid func(error) error) (any, error) {
+	u, err := strconv.ParseUint(value, 10, bits)
+	if err != nil {
+		return nil, invalid(err)
+	}
+	return goType + "(" + strconv.FormatUint(u, 10) + ")", nil
+}
+
+func floatLiteral(goType string, value string, bits int, invalid func(error) error) (any, error) {
+	f, err := strconv.ParseFloat(value, bits)
+	if err != nil {
+		return nil, invalid(err)
+	}
+	return goType + "(" + strconv.FormatFloat(f, 'g', -1, bits) + ")", nil
+}
+
+// maskGetter returns the expression for the paths of the update_mask field mask of the
+// message, or an empty string if it has none. Other masks, e.g. a read_mask, do not gate rules
+func maskGetter(m *protogen.Message) string {
+	for _, field := range m.Fields {
+		if field.Desc.Name() == updateMaskField && field.Message != nil && field.Message.Desc.FullName() == fieldMaskName && !field.Desc.IsList() {
+			return "msg.Get" + field.GoName + "().GetPaths()..."
+		}
+	}
+	return ""
+}
+
+func flattenMessages(messages []*protogen.Message) []*protogen.Message {
+	var all []*protogen.Message
+	for _, m := range messages {
+		if m.Desc.IsMapEntry() {
+			continue
+		}
+		all = append(all, m)
+		all = append(all, flattenMessages(m.Messages)...)
+	}
+	return all
+}
+
+func onStack(m *protogen.Message, stack []*protogen.Message) bool {
+	for _, s := range stack {
+		if s == m {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,274 @@
+package main
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	resdesv1 "github.com/signal426/resdes/proto/gen/resdes/v1"
+	v1 "github.com/signal426/resdes/test_protos/gen/test_protos/resdes/v1"
+	"github.com/signal426/resdes/test_protos/gen/test_protos/resdes/v1/v1resdes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+const testProtoGenerated = "../../test_protos/gen/test_protos/resdes/v1/v1resdes/test.resdes.go"
+
+// runGenerator runs the generator against the files as protoc would
+func runGenerator(t *testing.T, files ...*descriptorpb.FileDescriptorProto) *pluginpb.CodeGeneratorResponse {
+	t.Helper()
+	req := &pluginpb.CodeGeneratorRequest{
+		FileToGenerate: []string{files[len(files)-1].GetName()},
+		ProtoFile:      files,
+		Parameter:      proto.String("module=github.com/signal426/resdes"),
+	}
+	gen, err := protogen.Options{}.New(req)
+	require.NoError(t, err)
+	for _, f := range gen.Files {
+		if !f.Generate {
+			continue
+		}
+		if err := generateFile(gen, f); err != nil {
+			gen.Error(err)
+		}
+	}
+	return gen.Response()
+}
+
+// withDependencies returns the file and its transitive imports, dependencies first
+func withDependencies(fd protoreflect.FileDescriptor) []*descriptorpb.FileDescriptorProto {
+	var files []*descriptorpb.FileDescriptorProto
+	imports := fd.Imports()
+	for i := 0; i < imports.Len(); i++ {
+		files = append(files, withDependencies(imports.Get(i).FileDescriptor)...)
+	}
+	return append(files, protodesc.ToFileDescriptorProto(fd))
+}
+
+// testFile returns a proto3 file of the invalid package holding the messages
+func testFile(messages ...*descriptorpb.DescriptorProto) *descriptorpb.FileDescriptorProto {
+	return &descriptorpb.FileDescriptorProto{
+		Name:       proto.String("invalid.proto"),
+		Package:    proto.String("invalid"),
+		Syntax:     proto.String("proto3"),
+		Dependency: []string{"google/protobuf/field_mask.proto", "resdes/v1/options.proto"},
+		Options: &descriptorpb.FileOptions{
+			GoPackage: proto.String("github.com/signal426/resdes/invalid;invalid"),
+		},
+		MessageType: messages,
+	}
+}
+
+// testField returns a field of the supplied type. typeName is the name of the message type, if any
+func testField(name string, number int32, label descriptorpb.FieldDescriptorProto_Label, typ descriptorpb.FieldDescriptorProto_Type, typeName string, rules *resdesv1.FieldRules) *descriptorpb.FieldDescriptorProto {
+	field := &descriptorpb.FieldDescriptorProto{
+		Name:     proto.String(name),
+		JsonName: proto.String(name),
+		Number:   proto.Int32(number),
+		Label:    label.Enum(),
+		Type:     typ.Enum(),
+	}
+	if typeName != "" {
+		field.TypeName = proto.String(typeName)
+	}
+	if rules != nil {
+		field.Options = &descriptorpb.FieldOptions{}
+		proto.SetExtension(field.Options, resdesv1.E_Field, rules)
+	}
+	return field
+}
+
+// withTestDependencies returns the imports of a test file followed by the file
+func withTestDependencies(file *descriptorpb.FileDescriptorProto) []*descriptorpb.FileDescriptorProto {
+	files := withDependencies(fieldmaskpb.File_google_protobuf_field_mask_proto)
+	files = append(files, withDependencies(resdesv1.File_resdes_v1_options_proto)...)
+	return append(files, file)
+}
+
+func TestGenerator(t *testing.T) {
+	t.Run("it should match the checked in test proto validators", func(t *testing.T) {
+		// arrange
+		expected, err := os.ReadFile(testProtoGenerated)
+		require.NoError(t, err)
+
+		// act
+		res := runGenerator(t, withDependencies(v1.File_resdes_v1_test_proto)...)
+
+		// assert
+		assert.Empty(t, res.GetError())
+		require.Len(t, res.GetFile(), 1)
+		assert.Equal(t, "test_protos/gen/test_protos/resdes/v1/v1resdes/test.resdes.go", res.GetFile()[0].GetName())
+		assert.Equal(t, string(expected), res.GetFile()[0].GetContent())
+	})
+
+	t.Run("it should reject length rules on non-string fields", func(t *testing.T) {
+		// arrange
+		options := &descriptorpb.FieldOptions{}
+		proto.SetExtension(options, resdesv1.E_Field, &resdesv1.FieldRules{MinLen: proto.Uint32(1)})
+		file := &descriptorpb.FileDescriptorProto{
+			Name:    proto.String("invalid.proto"),
+			Package: proto.String("invalid"),
+			Syntax:  proto.String("proto3"),
+			Options: &descriptorpb.FileOptions{
+				GoPackage: proto.String("github.com/signal426/resdes/invalid;invalid"),
+			},
+			MessageType: []*descriptorpb.DescriptorProto{
+				{
+					Name: proto.String("Page"),
+					Field: []*descriptorpb.FieldDescriptorProto{
+						{
+							Name:     proto.String("size"),
+							JsonName: proto.String("size"),
+							Number:   proto.Int32(1),
+							Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+							Type:     descriptorpb.FieldDescriptorProto_TYPE_INT32.Enum(),
+							Options:  options,
+						},
+					},
+				},
+			},
+		}
+
+		// act
+		res := runGenerator(t, file)
+
+		// assert
+		assert.Equal(t, "resdes: invalid.Page.size: min_len and max_len only apply to string fields", res.GetError())
+	})
+
+	t.Run("it should not gate rules on a read mask", func(t *testing.T) {
+		// arrange
+		file := testFile(&descriptorpb.DescriptorProto{
+			Name: proto.String("GetPageRequest"),
+			Field: []*descriptorpb.FieldDescriptorProto{
+				testField("name", 1, descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL, descriptorpb.FieldDescriptorProto_TYPE_STRING, "",
+					&resdesv1.FieldRules{Required: true, InMaskOnly: true}),
+				testField("read_mask", 2, descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, ".google.protobuf.FieldMask", nil),
+			},
+		})
+
+		// act
+		res := runGenerator(t, withTestDependencies(file)...)
+
+		// assert
+		assert.Empty(t, res.GetError())
+		require.Len(t, res.GetFile(), 1)
+		assert.Equal(t, `// Code generated by protoc-gen-resdes. DO NOT EDIT.
+// source: invalid.proto
+
+package invalidresdes
+
+import (
+	resdes "github.com/signal426/resdes"
+	invalid "github.com/signal426/resdes/invalid"
+)
+
+// NewGetPageRequestValidator returns a validator for the rules declared on invalid.GetPageRequest
+func NewGetPageRequestValidator(msg *invalid.GetPageRequest) *resdes.DefaultMessageValidator[*invalid.GetPageRequest] {
+	v := resdes.ForMessage[*invalid.GetPageRequest]()
+	v.AssertNonZeroWhenInMask("name", msg.GetName())
+	return v
+}
+`, res.GetFile()[0].GetContent())
+	})
+
+	t.Run("it should reject rules of messages nested in repeated elements", func(t *testing.T) {
+		// arrange
+		optional, repeated := descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL, descriptorpb.FieldDescriptorProto_LABEL_REPEATED
+		message := descriptorpb.FieldDescriptorProto_TYPE_MESSAGE
+		file := testFile(
+			&descriptorpb.DescriptorProto{
+				Name: proto.String("Inner"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					testField("name", 1, optional, descriptorpb.FieldDescriptorProto_TYPE_STRING, "", &resdesv1.FieldRules{Required: true}),
+				},
+			},
+			&descriptorpb.DescriptorProto{
+				Name: proto.String("Outer"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					testField("inner", 1, optional, message, ".invalid.Inner", nil),
+				},
+			},
+			&descriptorpb.DescriptorProto{
+				Name: proto.String("Holder"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					testField("outers", 1, repeated, message, ".invalid.Outer", nil),
+				},
+			},
+		)
+
+		// act
+		res := runGenerator(t, withTestDependencies(file)...)
+
+		// assert
+		assert.Equal(t, "resdes: invalid.Outer.inner: rules of messages nested in the elements of invalid.Holder.outers are not supported", res.GetError())
+	})
+}
+
+func TestGeneratedValidators(t *testing.T) {
+	t.Run("it should assert the declared rules", func(t *testing.T) {
+		// arrange
+		req := &v1.UpdateUserRequest{
+			User: &v1.User{
+				FirstName: "bob",
+				LastName:  "b",
+				PrimaryAddress: &v1.Address{
+					Line1: "a",
+				},
+			},
+			UpdateMask: &fieldmaskpb.FieldMask{
+				Paths: []string{"user.first_name", "user.last_name"},
+			},
+		}
+
+		// act
+		err := v1resdes.NewUpdateUserRequestValidator(req).Exec(context.Background(), req)
+
+		// assert
+		assert.Error(t, err)
+		assert.Equal(t, []string{"user.id", "user.first_name", "user.last_name"}, err.Paths())
+	})
+
+	t.Run("it should assert the declared rules on every element of a repeated field", func(t *testing.T) {
+		// arrange
+		req := &v1.CreateUserRequest{
+			User: &v1.User{
+				Id: "abc123",
+				SecondaryAddresses: []*v1.Address{
+					{Line1: "a"},
+					{Line2: "b"},
+					{Line1: "c", Line2: strings.Repeat("d", 101)},
+				},
+			},
+		}
+
+		// act
+		err := v1resdes.NewCreateUserRequestValidator(req).Exec(context.Background(), req)
+
+		// assert
+		assert.Error(t, err)
+		assert.Equal(t, []string{"user.secondary_addresses[1].line1", "user.secondary_addresses[2].line2"}, err.Paths())
+	})
+
+	t.Run("it should skip the rules of unset nested messages", func(t *testing.T) {
+		// arrange
+		res := &v1.CreateUserResponse{}
+		req := &v1.CreateUserRequest{}
+
+		// act
+		resErr := v1resdes.NewCreateUserResponseValidator(res).Exec(context.Background(), res)
+		reqErr := v1resdes.NewCreateUserRequestValidator(req).Exec(context.Background(), req)
+
+		// assert
+		assert.Nil(t, resErr)
+		assert.Equal(t, []string{"user"}, reqErr.Paths())
+	})
+}
@@ -0,0 +1,27 @@
+// protoc-gen-resdes generates DefaultMessageValidator constructors from the
+// resdes.v1.field options declared on the fields of each message.
+//
+// The constructors are written to a <package>resdes subpackage next to the
+// package generated by protoc-gen-go, so that packages holding the messages
+// do not need to import resdes.
+package main
+
+import (
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+func main() {
+	protogen.Options{}.Run(func(gen *protogen.Plugin) error {
+		gen.SupportedFeatures = uint64(pluginpb.CodeGeneratorResponse_FEATURE_PROTO3_OPTIONAL)
+		for _, f := range gen.Files {
+			if !f.Generate {
+				continue
+			}
+			if err := generateFile(gen, f); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,96 @@
+package resdes
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// predicate reports whether a rule with the When condition applies to the message,
+// along with the path of the field that triggered it
+type predicate func(m protoreflect.Message) (trigger string, ok bool)
+
+// RequireWhen assert that the value at the supplied path is not a zero-value when the predicate holds
+// for the message. The trigger is the path of the field the predicate looks at, and is reported on the
+// field error. Panics if the path does not exist in the message descriptor
+func (s *DefaultMessageValidator[T]) RequireWhen(path string, trigger string, when func(T) bool) *DefaultMessageValidator[T] {
+	return s.requireWhen(path, NonZero, func(m protoreflect.Message) (string, bool) {
+		return trigger, when(m.Interface().(T))
+	})
+}
+
+// ForbidWhen assert that the value at the supplied path is a zero-value when the predicate holds for the message
+func (s *DefaultMessageValidator[T]) ForbidWhen(path string, trigger string, when func(T) bool) *DefaultMessageValidator[T] {
+	return s.requireWhen(path, Forbidden, func(m protoreflect.Message) (string, bool) {
+		return trigger, when(m.Interface().(T))
+	})
+}
+
+// RequiredIf assert that the value at the supplied path is not a zero-value when the sibling field is set,
+// e.g. line2 is required if line1 is set. Both paths are relative to the message
+func (s *DefaultMessageValidator[T]) RequiredIf(path string, sibling string) *DefaultMessageValidator[T] {
+	set := s.isSet(sibling)
+	return s.requireWhen(path, NonZero, func(m protoreflect.Message) (string, bool) {
+		return sibling, set(m)
+	})
+}
+
+// ForbiddenIf assert that the value at the supplied path is a zero-value when the sibling field is set
+func (s *DefaultMessageValidator[T]) ForbiddenIf(path string, sibling string) *DefaultMessageValidator[T] {
+	set := s.isSet(sibling)
+	return s.requireWhen(path, Forbidden, func(m protoreflect.Message) (string, bool) {
+		return sibling, set(m)
+	})
+}
+
+// ForbiddenUnless assert that the value at the supplied path is a zero-value unless the sibling field is set,
+// e.g. end_time is forbidden unless start_time is set
+func (s *DefaultMessageValidator[T]) ForbiddenUnless(path string, sibling string) *DefaultMessageValidator[T] {
+	set := s.isSet(sibling)
+	return s.requireWhen(path, Forbidden, func(m protoreflect.Message) (string, bool) {
+		return sibling, !set(m)
+	})
+}
+
+// MutuallyExclusive assert that at most one of the fields at the supplied paths is set. Every set field
+// after the first is reported with the Forbidden policy, triggered by the first set field. Panics if fewer
+// than two paths are supplied or a path does not exist in the message descriptor
+func (s *DefaultMessageValidator[T]) MutuallyExclusive(paths ...string) *DefaultMessageValidator[T] {
+	if len(paths) < 2 {
+		panic(fmt.Sprintf("resdes: MutuallyExclusive needs at least two paths, got %v", paths))
+	}
+	sets := make([]func(protoreflect.Message) bool, len(paths))
+	for i, path := range paths {
+		sets[i] = s.isSet(path)
+	}
+	for i, path := range paths[1:] {
+		s.requireWhen(path, Forbidden, func(m protoreflect.Message) (string, bool) {
+			for j, set := range sets[:i+1] {
+				if set(m) {
+					return paths[j], true
+				}
+			}
+			return "", false
+		})
+	}
+	return s
+}
+
+func (s *DefaultMessageValidator[T]) requireWhen(path string, policy Policy, when predicate) *DefaultMessageValidator[T] {
+	s.require(path, policy, When, nil)
+	s.rules[len(s.rules)-1].(*Field).when = when
+	return s
+}
+
+// isSet resolves the path against the message descriptor and returns a func
+// reporting whether the value at the path is set in a message
+func (s *DefaultMessageValidator[T]) isSet(path string) func(protoreflect.Message) bool {
+	var zero T
+	fds, err := resolvePath(zero.ProtoReflect().Descriptor(), path)
+	if err != nil {
+		panic(fmt.Sprintf("resdes: %v", err))
+	}
+	return func(m protoreflect.Message) bool {
+		return !isZero(valueAt(m, fds))
+	}
+}
@@ -0,0 +1,121 @@
+package resdes
+
+import (
+	"context"
+	"testing"
+
+	v1 "github.com/signal426/resdes/test_protos/gen/test_protos/resdes/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConditionalValidations(t *testing.T) {
+	t.Run("it should require a field when its sibling is set", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			address *v1.Address
+			err     error
+		}{
+			{name: "sibling set", address: &v1.Address{Line1: "1 Main St"}, err: ErrFieldMustNotBeZeroFailed},
+			{name: "both set", address: &v1.Address{Line1: "1 Main St", Line2: "Apt 2"}},
+			{name: "sibling not set", address: &v1.Address{}},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// act
+				err := ForMessage[*v1.Address]().
+					RequiredIf("line2", "line1").
+					Exec(context.Background(), tt.address)
+
+				// assert
+				if tt.err == nil {
+					assert.Nil(t, err)
+					return
+				}
+				assert.ErrorIs(t, err, tt.err)
+				assert.Equal(t, "line1", err.FieldErrors[0].Trigger)
+				assert.Equal(t, NonZero, err.FieldErrors[0].Policy)
+			})
+		}
+	})
+
+	t.Run("it should forbid a field when its sibling is or is not set", func(t *testing.T) {
+		// arrange
+		user := &v1.User{
+			FirstName:      "bob",
+			PrimaryAddress: &v1.Address{Line2: "Apt 2"},
+		}
+		expected := &ValidationErrors{
+			FieldErrors: []*FieldError{
+				{
+					Path:    "primary_address.line2",
+					Policy:  Forbidden,
+					Trigger: "primary_address.line1",
+					Err:     newFieldTriggeredErr("primary_address.line1", newFieldForbiddenErr("primary_address.line2", "Apt 2")),
+				},
+			},
+		}
+
+		// act
+		err := ForMessage[*v1.User]().
+			ForbiddenIf("id", "first_name").
+			ForbiddenIf("last_name", "first_name").
+			ForbiddenUnless("primary_address.line2", "primary_address.line1").
+			ForbiddenUnless("id", "secondary_addresses").
+			Exec(context.Background(), user)
+
+		// assert
+		assert.ErrorIs(t, err, ErrFieldForbidden)
+		assert.Equal(t, expected.Error(), err.Error())
+	})
+
+	t.Run("it should report every mutually exclusive field after the first", func(t *testing.T) {
+		// arrange
+		user := &v1.User{
+			FirstName: "bob",
+			LastName:  "Bobson",
+			Id:        "123",
+		}
+
+		// act
+		err := ForMessage[*v1.User]().
+			MutuallyExclusive("primary_address", "first_name", "last_name", "id").
+			Exec(context.Background(), user)
+
+		// assert
+		assert.ErrorIs(t, err, ErrFieldForbidden)
+		assert.Equal(t, []string{"last_name", "id"}, err.Paths())
+		assert.Equal(t, "first_name", err.FieldErrors[0].Trigger)
+		assert.Equal(t, "first_name", err.FieldErrors[1].Trigger)
+	})
+
+	t.Run("it should evaluate custom predicates against the message", func(t *testing.T) {
+		// arrange
+		req := &v1.CreateUserRequest{
+			User: &v1.User{Status: v1.UserStatus_USER_STATUS_SUSPENDED},
+		}
+		suspended := func(r *v1.CreateUserRequest) bool {
+			return r.GetUser().GetStatus() == v1.UserStatus_USER_STATUS_SUSPENDED
+		}
+
+		// act
+		err := ForMessage[*v1.CreateUserRequest]().
+			RequireWhen("user.labels", "user.status", suspended).
+			ForbidWhen("user.email", "user.status", suspended).
+			Exec(context.Background(), req)
+
+		// assert
+		assert.ErrorIs(t, err, ErrFieldMustNotBeZeroFailed)
+		assert.Equal(t, []string{"user.labels"}, err.Paths())
+		assert.Equal(t, "user.status", err.FieldErrors[0].Trigger)
+		assert.Contains(t, err.Error(), "triggered by: user.status")
+	})
+
+	t.Run("it should panic on an unknown sibling", func(t *testing.T) {
+		assert.Panics(t, func() { ForMessage[*v1.User]().RequiredIf("id", "nope") })
+	})
+
+	t.Run("it should panic on fewer than two mutually exclusive paths", func(t *testing.T) {
+		assert.PanicsWithValue(t, "resdes: MutuallyExclusive needs at least two paths, got []", func() { ForMessage[*v1.User]().MutuallyExclusive() })
+		assert.Panics(t, func() { ForMessage[*v1.User]().MutuallyExclusive("id") })
+	})
+}
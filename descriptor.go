@@ -0,0 +1,139 @@
+package resdes
+
+import (
+	"slices"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+const (
+	fieldMaskName        = protoreflect.FullName("google.protobuf.FieldMask")
+	defaultReadMaskField = "read_mask"
+)
+
+// resolvePath resolves a dotted field path against the message descriptor.
+// Each segment may be the proto (snake_case) or JSON (camelCase) name of the field
+func resolvePath(md protoreflect.MessageDescriptor, path string) ([]protoreflect.FieldDescriptor, error) {
+	segments := strings.Split(path, ".")
+	fds := make([]protoreflect.FieldDescriptor, 0, len(segments))
+	for _, segment := range segments {
+		if md == nil {
+			return nil, newFieldPathNotFoundErr(path, segment)
+		}
+		fd := md.Fields().ByName(protoreflect.Name(segment))
+		if fd == nil {
+			fd = md.Fields().ByJSONName(segment)
+		}
+		if fd == nil {
+			return nil, newFieldPathNotFoundErr(path, segment)
+		}
+		fds = append(fds, fd)
+		md = nil
+		if !fd.IsList() && !fd.IsMap() {
+			md = fd.Message()
+		}
+	}
+	return fds, nil
+}
+
+// valueAt returns the value at the resolved path of the message as the type
+// its generated getter would return. Unset messages along the path resolve to
+// the zero value of the field
+func valueAt(m protoreflect.Message, fds []protoreflect.FieldDescriptor) any {
+	for _, fd := range fds[:len(fds)-1] {
+		m = m.Get(fd).Message()
+	}
+	leaf := fds[len(fds)-1]
+	return goValue(leaf, m.Get(leaf))
+}
+
+func goValue(fd protoreflect.FieldDescriptor, v protoreflect.Value) any {
+	switch {
+	case fd.IsList():
+		if v.List().Len() == 0 {
+			return nil
+		}
+		return v.List()
+	case fd.IsMap():
+		if v.Map().Len() == 0 {
+			return nil
+		}
+		return v.Map()
+	}
+	return goElement(fd, v)
+}
+
+// goElement returns a singular value, or an element of a repeated or map field,
+// as the type a generated getter would return
+func goElement(fd protoreflect.FieldDescriptor, v protoreflect.Value) any {
+	switch {
+	case fd.Message() != nil:
+		return v.Message().Interface()
+	case fd.Enum() != nil:
+		if et, err := protoregistry.GlobalTypes.FindEnumByName(fd.Enum().FullName()); err == nil {
+			return et.New(v.Enum())
+		}
+		return v.Enum()
+	default:
+		return v.Interface()
+	}
+}
+
+// resolveMaskPath resolves a field mask path against the message descriptor. On failure it returns
+// the segment that could not be resolved. Field masks cannot address the elements of repeated
+// or map fields, so a path may end at one but not go through it
+func resolveMaskPath(md protoreflect.MessageDescriptor, path string) ([]protoreflect.FieldDescriptor, string, error) {
+	segments := strings.Split(path, ".")
+	fds := make([]protoreflect.FieldDescriptor, 0, len(segments))
+	for i, segment := range segments {
+		fd := md.Fields().ByName(protoreflect.Name(segment))
+		if fd == nil {
+			fd = md.Fields().ByJSONName(segment)
+		}
+		if fd == nil {
+			return nil, segment, ErrFieldPathNotFound
+		}
+		fds = append(fds, fd)
+		if i == len(segments)-1 {
+			break
+		}
+		if fd.IsList() || fd.IsMap() {
+			return nil, segments[i+1], ErrFieldMaskPathThroughRepeated
+		}
+		if md = fd.Message(); md == nil {
+			return nil, segments[i+1], ErrFieldPathNotFound
+		}
+	}
+	return fds, "", nil
+}
+
+// maskFieldName returns the name of the first google.protobuf.FieldMask field of the message, skipping the excluded names
+func maskFieldName(md protoreflect.MessageDescriptor, exclude ...string) (string, bool) {
+	fields := md.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if isMaskField(fd) && !slices.Contains(exclude, string(fd.Name())) {
+			return string(fd.Name()), true
+		}
+	}
+	return "", false
+}
+
+// isMaskField reports whether the field is a singular google.protobuf.FieldMask
+func isMaskField(fd protoreflect.FieldDescriptor) bool {
+	return fd != nil && fd.Message() != nil && fd.Message().FullName() == fieldMaskName && !fd.IsList()
+}
+
+// updateMaskFieldName returns the name of the update_mask field of the message, its first
+// google.protobuf.FieldMask field other than read_mask, or update_mask if it has neither
+func updateMaskFieldName(md protoreflect.MessageDescriptor) string {
+	if isMaskField(md.Fields().ByName(defaultMaskField)) {
+		return defaultMaskField
+	}
+	if name, ok := maskFieldName(md, defaultReadMaskField); ok {
+		return name
+	}
+	return defaultMaskField
+}
@@ -0,0 +1,167 @@
+package resdes
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// rule is evaluated against a message, adding any field errors to errs
+type rule interface {
+	validate(m protoreflect.Message, errs *ValidationErrors)
+}
+
+var (
+	_ rule = (*Field)(nil)
+	_ rule = (*eachRule)(nil)
+	_ rule = (*oneofRule)(nil)
+	_ rule = (*maskRule)(nil)
+)
+
+// EachRules holds the rules to run on every element of a repeated or map field.
+// Paths are relative to the element. An empty path asserts on the element itself
+type EachRules struct {
+	// descriptor of the elements, the value descriptor for map fields
+	elem protoreflect.FieldDescriptor
+
+	// rules to run on each element, with paths relative to the element
+	fields []*Field
+}
+
+// Require assert that the value at the supplied element path is not a zero-value
+func (e *EachRules) Require(path string) *EachRules {
+	return e.add(path, NonZero, Always, nil)
+}
+
+// RequireNotEqualTo assert that the value at the supplied element path is not equal to the supplied target value
+func (e *EachRules) RequireNotEqualTo(path string, notEqualTo any) *EachRules {
+	return e.add(path, NotEqualTo, Always, notEqualTo)
+}
+
+// RequireEqualTo assert that the value at the supplied element path is equal to the supplied target value
+func (e *EachRules) RequireEqualTo(path string, equalTo any) *EachRules {
+	return e.add(path, MustEqual, Always, equalTo)
+}
+
+// RequireWhenInMask same as Require, but only executes if the element path is in the field mask
+func (e *EachRules) RequireWhenInMask(path string) *EachRules {
+	return e.add(path, NonZero, InMask, nil)
+}
+
+// RequireNotEqualToWhenInMask same as RequireNotEqualTo, but only executes if the element path is in the field mask
+func (e *EachRules) RequireNotEqualToWhenInMask(path string, notEqualTo any) *EachRules {
+	return e.add(path, NotEqualTo, InMask, notEqualTo)
+}
+
+// RequireEqualToWhenInMask same as RequireEqualTo, but only executes if the element path is in the field mask
+func (e *EachRules) RequireEqualToWhenInMask(path string, equalTo any) *EachRules {
+	return e.add(path, MustEqual, InMask, equalTo)
+}
+
+// RequireMinLength assert that the string at the supplied element path has at least min runes
+func (e *EachRules) RequireMinLength(path string, min int) *EachRules {
+	return e.add(path, MinLength, Always, min)
+}
+
+// RequireMaxLength assert that the string at the supplied element path has at most max runes
+func (e *EachRules) RequireMaxLength(path string, max int) *EachRules {
+	return e.add(path, MaxLength, Always, max)
+}
+
+// RequireMinLengthWhenInMask same as RequireMinLength, but only executes if the element path is in the field mask
+func (e *EachRules) RequireMinLengthWhenInMask(path string, min int) *EachRules {
+	return e.add(path, MinLength, InMask, min)
+}
+
+// RequireMaxLengthWhenInMask same as RequireMaxLength, but only executes if the element path is in the field mask
+func (e *EachRules) RequireMaxLengthWhenInMask(path string, max int) *EachRules {
+	return e.add(path, MaxLength, InMask, max)
+}
+
+func (e *EachRules) add(path string, policy Policy, condition Condition, cmpTo any) *EachRules {
+	var fds []protoreflect.FieldDescriptor
+	if path != "" {
+		md := e.elem.Message()
+		if md == nil {
+			panic(fmt.Sprintf("resdes: %v", newFieldPathNotFoundErr(path, path)))
+		}
+		var err error
+		if fds, err = resolvePath(md, path); err != nil {
+			panic(fmt.Sprintf("resdes: %v", err))
+		}
+	}
+	e.fields = append(e.fields, newPathField(path, fds, policy, condition, cmpTo, nil))
+	return e
+}
+
+// eachRule runs EachRules on every element of the repeated or map field at path
+type eachRule struct {
+	path  string
+	fds   []protoreflect.FieldDescriptor
+	rules *EachRules
+	paths map[string]struct{}
+}
+
+func (r *eachRule) validate(m protoreflect.Message, errs *ValidationErrors) {
+	for _, fd := range r.fds[:len(r.fds)-1] {
+		m = m.Get(fd).Message()
+	}
+	fd := r.fds[len(r.fds)-1]
+	if fd.IsList() {
+		list := m.Get(fd).List()
+		for i := 0; i < list.Len(); i++ {
+			r.validateElement(IndexPath(r.path, i), list.Get(i), errs)
+		}
+		return
+	}
+	entries := m.Get(fd).Map()
+	keys := make([]protoreflect.MapKey, 0, entries.Len())
+	entries.Range(func(k protoreflect.MapKey, _ protoreflect.Value) bool {
+		keys = append(keys, k)
+		return true
+	})
+	slices.SortFunc(keys, compareMapKeys)
+	for _, k := range keys {
+		r.validateElement(KeyPath(r.path, k.Interface()), entries.Get(k), errs)
+	}
+}
+
+func (r *eachRule) validateElement(elemPath string, v protoreflect.Value, errs *ValidationErrors) {
+	for _, f := range r.rules.fields {
+		path := elemPath
+		var value any
+		if f.fds == nil {
+			value = goElement(r.rules.elem, v)
+		} else {
+			path += "." + f.path
+			value = valueAt(v.Message(), f.fds)
+		}
+		field := NewField(path, value, f.policy, f.condition, f.cmpTo, r.paths)
+		if err := field.Validate(); err != nil {
+			errs.addFieldErr(field, err)
+		}
+	}
+}
+
+// compareMapKeys orders map keys of the same kind so that entries are validated deterministically
+func compareMapKeys(a, b protoreflect.MapKey) int {
+	switch av := a.Interface().(type) {
+	case string:
+		return cmp.Compare(av, b.String())
+	case int32, int64:
+		return cmp.Compare(a.Int(), b.Int())
+	case uint32, uint64:
+		return cmp.Compare(a.Uint(), b.Uint())
+	case bool:
+		if av == b.Bool() {
+			return 0
+		}
+		if av {
+			return 1
+		}
+		return -1
+	}
+	return 0
+}
@@ -0,0 +1,96 @@
+package resdes
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// AssertEnumDefined assert that the supplied enum is a value defined in its enum.
+// Proto3 enums are open, so any number can be received on the wire
+func (s *DefaultMessageValidator[T]) AssertEnumDefined(path string, value protoreflect.Enum) *DefaultMessageValidator[T] {
+	s.rules = append(s.rules, NewField(path, value, EnumDefined, Always, nil, s.paths))
+	return s
+}
+
+// AssertEnumSpecified assert that the supplied enum is a defined value other than the zero (e.g. *_UNSPECIFIED) value
+func (s *DefaultMessageValidator[T]) AssertEnumSpecified(path string, value protoreflect.Enum) *DefaultMessageValidator[T] {
+	s.rules = append(s.rules, NewField(path, value, EnumSpecified, Always, nil, s.paths))
+	return s
+}
+
+// AssertEnumIn assert that the supplied enum is one of the allowed enum value names (e.g. USER_STATUS_ACTIVE).
+// Panics if a name is not a value of the enum
+func (s *DefaultMessageValidator[T]) AssertEnumIn(path string, value protoreflect.Enum, allowed ...string) *DefaultMessageValidator[T] {
+	return s.enumIn(path, value, Always, allowed)
+}
+
+// AssertEnumDefinedWhenInMask same as AssertEnumDefined, but only executes if the supplied path is in the field mask
+func (s *DefaultMessageValidator[T]) AssertEnumDefinedWhenInMask(path string, value protoreflect.Enum) *DefaultMessageValidator[T] {
+	s.rules = append(s.rules, NewField(path, value, EnumDefined, InMask, nil, s.paths))
+	return s
+}
+
+// AssertEnumSpecifiedWhenInMask same as AssertEnumSpecified, but only executes if the supplied path is in the field mask
+func (s *DefaultMessageValidator[T]) AssertEnumSpecifiedWhenInMask(path string, value protoreflect.Enum) *DefaultMessageValidator[T] {
+	s.rules = append(s.rules, NewField(path, value, EnumSpecified, InMask, nil, s.paths))
+	return s
+}
+
+// AssertEnumInWhenInMask same as AssertEnumIn, but only executes if the supplied path is in the field mask
+func (s *DefaultMessageValidator[T]) AssertEnumInWhenInMask(path string, value protoreflect.Enum, allowed ...string) *DefaultMessageValidator[T] {
+	return s.enumIn(path, value, InMask, allowed)
+}
+
+func (s *DefaultMessageValidator[T]) enumIn(path string, value protoreflect.Enum, condition Condition, allowed []string) *DefaultMessageValidator[T] {
+	if value != nil {
+		values := value.Descriptor().Values()
+		for _, name := range allowed {
+			if values.ByName(protoreflect.Name(name)) == nil {
+				panic(fmt.Sprintf("resdes: %s: %s is not a value of %s", path, name, value.Descriptor().FullName()))
+			}
+		}
+	}
+	s.rules = append(s.rules, NewField(path, value, EnumIn, condition, allowed, s.paths))
+	return s
+}
+
+// checkEnum checks the value against the values defined in its enum descriptor
+func (f Field) checkEnum() error {
+	e, ok := f.value.(protoreflect.Enum)
+	if !ok {
+		return newFieldsNotComparableErr(f.path, reflect.TypeOf(f.value), reflect.TypeOf((*protoreflect.Enum)(nil)).Elem())
+	}
+	v := e.Descriptor().Values().ByNumber(e.Number())
+	name := enumValueName(e.Number(), v)
+	switch f.policy {
+	case EnumDefined:
+		if v == nil {
+			return newFieldEnumErr(f.path, name, ErrFieldEnumUndefined)
+		}
+	case EnumSpecified:
+		if v == nil {
+			return newFieldEnumErr(f.path, name, ErrFieldEnumUndefined)
+		}
+		if e.Number() == 0 {
+			return newFieldEnumErr(f.path, name, ErrFieldEnumUnspecified)
+		}
+	case EnumIn:
+		allowed, _ := f.cmpTo.([]string)
+		if v == nil || !slices.Contains(allowed, string(v.Name())) {
+			return newFieldEnumNotAllowedErr(f.path, name, allowed)
+		}
+	}
+	return nil
+}
+
+// enumValueName returns the name of the enum value, or its number if it is not defined in the enum
+func enumValueName(n protoreflect.EnumNumber, v protoreflect.EnumValueDescriptor) string {
+	if v == nil {
+		return strconv.Itoa(int(n))
+	}
+	return string(v.Name())
+}
@@ -0,0 +1,102 @@
+package resdes
+
+import (
+	"context"
+	"testing"
+
+	v1 "github.com/signal426/resdes/test_protos/gen/test_protos/resdes/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnumValidations(t *testing.T) {
+	t.Run("it should reject values not defined in the enum", func(t *testing.T) {
+		// arrange
+		user := &v1.User{Status: v1.UserStatus(42)}
+		expected := &ValidationErrors{
+			FieldErrors: []*FieldError{
+				{
+					Path:   "status",
+					Policy: EnumDefined,
+					Err:    newFieldEnumErr("status", "42", ErrFieldEnumUndefined),
+				},
+			},
+		}
+
+		// act
+		err := ForMessage[*v1.User]().
+			AssertEnumDefined("status", user.GetStatus()).
+			Exec(context.Background(), user)
+
+		// assert
+		assert.ErrorIs(t, err, ErrFieldEnumUndefined)
+		assert.Equal(t, expected.Error(), err.Error())
+	})
+
+	t.Run("it should reject the unspecified value by name", func(t *testing.T) {
+		// arrange
+		user := &v1.User{}
+
+		// act
+		err := ForMessage[*v1.User]().
+			AssertEnumDefined("status", user.GetStatus()).
+			AssertEnumSpecified("status", user.GetStatus()).
+			Exec(context.Background(), user)
+
+		// assert
+		assert.ErrorIs(t, err, ErrFieldEnumUnspecified)
+		assert.Equal(t, []string{"status"}, err.Paths())
+		assert.Contains(t, err.Error(), "USER_STATUS_UNSPECIFIED")
+	})
+
+	t.Run("it should restrict values to the allowed names", func(t *testing.T) {
+		tests := []struct {
+			status v1.UserStatus
+			err    error
+		}{
+			{status: v1.UserStatus_USER_STATUS_ACTIVE},
+			{status: v1.UserStatus_USER_STATUS_SUSPENDED},
+			{status: v1.UserStatus_USER_STATUS_DELETED, err: ErrFieldEnumNotAllowed},
+			{status: v1.UserStatus(42), err: ErrFieldEnumNotAllowed},
+		}
+		for _, tt := range tests {
+			t.Run(tt.status.String(), func(t *testing.T) {
+				// act
+				err := ForMessage[*v1.User]().
+					AssertEnumIn("status", tt.status, "USER_STATUS_ACTIVE", "USER_STATUS_SUSPENDED").
+					Exec(context.Background(), &v1.User{Status: tt.status})
+
+				// assert
+				if tt.err == nil {
+					assert.Nil(t, err)
+					return
+				}
+				assert.ErrorIs(t, err, tt.err)
+				assert.Equal(t, []string{"USER_STATUS_ACTIVE", "USER_STATUS_SUSPENDED"}, err.FieldErrors[0].Expected)
+			})
+		}
+	})
+
+	t.Run("it should only check in-mask enums", func(t *testing.T) {
+		// arrange
+		req := &v1.UpdateUserRequest{User: &v1.User{}}
+
+		// act
+		err := ForMessage[*v1.UpdateUserRequest]("user.first_name").
+			AssertEnumSpecifiedWhenInMask("user.status", req.GetUser().GetStatus()).
+			AssertEnumDefinedWhenInMask("user.status", req.GetUser().GetStatus()).
+			AssertEnumInWhenInMask("user.status", req.GetUser().GetStatus(), "USER_STATUS_ACTIVE").
+			Exec(context.Background(), req)
+
+		// assert
+		assert.Nil(t, err)
+	})
+
+	t.Run("it should panic on an allowed name that is not a value of the enum", func(t *testing.T) {
+		assert.PanicsWithValue(t, "resdes: status: ACTVE is not a value of resdes.v1.UserStatus", func() {
+			ForMessage[*v1.User]().AssertEnumIn("status", v1.UserStatus_USER_STATUS_ACTIVE, "USER_STATUS_ACTIVE", "ACTVE")
+		})
+		assert.Panics(t, func() {
+			ForMessage[*v1.User]().AssertEnumInWhenInMask("status", v1.UserStatus_USER_STATUS_ACTIVE, "ACTVE")
+		})
+	})
+}
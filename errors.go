@@ -1,11 +1,17 @@
 package resdes
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"reflect"
+	"runtime/debug"
+	"strconv"
 	"strings"
+	"time"
 
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
@@ -18,8 +24,175 @@ var (
 	ErrFieldMustNotEqualFailed = errors.New("field set to forbidden value")
 	// ErrFieldMustNotBeZeroFailed returned when the supplied value matches its type's zero-value
 	ErrFieldMustNotBeZeroFailed = errors.New("field set to zero value")
+	// ErrFieldLengthTooShort returned when the supplied string has fewer runes than the minimum length
+	ErrFieldLengthTooShort = errors.New("field shorter than minimum length")
+	// ErrFieldLengthTooLong returned when the supplied string has more runes than the maximum length
+	ErrFieldLengthTooLong = errors.New("field longer than maximum length")
+	// ErrFieldPatternMismatch returned when the supplied string does not match the pattern
+	ErrFieldPatternMismatch = errors.New("field does not match pattern")
+	// ErrFieldMissingPrefix returned when the supplied string does not start with the prefix
+	ErrFieldMissingPrefix = errors.New("field does not have required prefix")
+	// ErrFieldMissingSuffix returned when the supplied string does not end with the suffix
+	ErrFieldMissingSuffix = errors.New("field does not have required suffix")
+	// ErrFieldMissingSubstring returned when the supplied string does not contain the substring
+	ErrFieldMissingSubstring = errors.New("field does not contain required substring")
+	// ErrFieldInvalidEmail returned when the supplied string is not a valid email address
+	ErrFieldInvalidEmail = errors.New("field is not a valid email address")
+	// ErrFieldInvalidHostname returned when the supplied string is not a valid hostname
+	ErrFieldInvalidHostname = errors.New("field is not a valid hostname")
+	// ErrFieldInvalidIP returned when the supplied string is not a valid IP address
+	ErrFieldInvalidIP = errors.New("field is not a valid ip address")
+	// ErrFieldInvalidURI returned when the supplied string is not a valid absolute URI
+	ErrFieldInvalidURI = errors.New("field is not a valid uri")
+	// ErrFieldInvalidUUID returned when the supplied string is not a valid UUID
+	ErrFieldInvalidUUID = errors.New("field is not a valid uuid")
+	// ErrFieldMustBeGreaterThan returned when the supplied number is not greater than the bound
+	ErrFieldMustBeGreaterThan = errors.New("field must be greater than bound")
+	// ErrFieldMustBeGreaterOrEqual returned when the supplied number is less than the bound
+	ErrFieldMustBeGreaterOrEqual = errors.New("field must be greater than or equal to bound")
+	// ErrFieldMustBeLessThan returned when the supplied number is not less than the bound
+	ErrFieldMustBeLessThan = errors.New("field must be less than bound")
+	// ErrFieldMustBeLessOrEqual returned when the supplied number is greater than the bound
+	ErrFieldMustBeLessOrEqual = errors.New("field must be less than or equal to bound")
+	// ErrFieldOutOfRange returned when the supplied number is outside of the inclusive bounds
+	ErrFieldOutOfRange = errors.New("field outside of range")
+	// ErrFieldEnumUndefined returned when the supplied enum number is not a value defined in the enum
+	ErrFieldEnumUndefined = errors.New("field value not defined in enum")
+	// ErrFieldEnumUnspecified returned when the supplied enum is the zero (unspecified) value
+	ErrFieldEnumUnspecified = errors.New("field enum value unspecified")
+	// ErrFieldEnumNotAllowed returned when the supplied enum is not one of the allowed values
+	ErrFieldEnumNotAllowed = errors.New("field enum value not allowed")
+	// ErrFieldOneofNotSet returned when none of the members of the oneof are set
+	ErrFieldOneofNotSet = errors.New("oneof has no member set")
+	// ErrFieldOneofMultipleSet returned when more than one member of the oneof is set
+	ErrFieldOneofMultipleSet = errors.New("oneof has more than one member set")
+	// ErrFieldForbidden returned when the supplied value is set but must not be
+	ErrFieldForbidden = errors.New("field must not be set")
+	// ErrFieldInvalidTimestamp returned when the supplied timestamp is out of the valid range
+	ErrFieldInvalidTimestamp = errors.New("field not a valid timestamp")
+	// ErrFieldTimestampNotInPast returned when the supplied timestamp is not before now
+	ErrFieldTimestampNotInPast = errors.New("field timestamp not in the past")
+	// ErrFieldTimestampNotInFuture returned when the supplied timestamp is not after now
+	ErrFieldTimestampNotInFuture = errors.New("field timestamp not in the future")
+	// ErrFieldTimestampOutsideWindow returned when the supplied timestamp is further from now than the window
+	ErrFieldTimestampOutsideWindow = errors.New("field timestamp outside of window")
+	// ErrFieldTimestampNotBefore returned when the supplied timestamp is not before the other field
+	ErrFieldTimestampNotBefore = errors.New("field timestamp not before other field")
+	// ErrFieldTimestampNotAfter returned when the supplied timestamp is not after the other field
+	ErrFieldTimestampNotAfter = errors.New("field timestamp not after other field")
+	// ErrFieldInvalidDuration returned when the supplied duration is out of the valid range
+	ErrFieldInvalidDuration = errors.New("field not a valid duration")
+	// ErrFieldDurationTooShort returned when the supplied duration is shorter than the minimum
+	ErrFieldDurationTooShort = errors.New("field duration too short")
+	// ErrFieldDurationTooLong returned when the supplied duration is longer than the maximum
+	ErrFieldDurationTooLong = errors.New("field duration too long")
+	// ErrFieldPathNotFound returned when a field path does not exist in the message descriptor
+	ErrFieldPathNotFound = errors.New("field path not found in message")
+	// ErrFieldMaskPathThroughRepeated returned when a field mask path goes through a repeated or map field
+	ErrFieldMaskPathThroughRepeated = errors.New("field mask path goes through a repeated field")
+	// ErrUnauthenticated can be wrapped by an Auther to signal that the caller could not be identified
+	ErrUnauthenticated = errors.New("unauthenticated")
+	// ErrPermissionDenied can be wrapped by an Auther to signal that the caller is not allowed to perform the request
+	ErrPermissionDenied = errors.New("permission denied")
+	// ErrAuthTimeout wrapped by a TimeoutError when the Auth stage runs past its timeout
+	ErrAuthTimeout = errors.New("auth stage timed out")
+	// ErrValidateTimeout wrapped by a TimeoutError when the Validate stage runs past its timeout
+	ErrValidateTimeout = errors.New("validate stage timed out")
+	// ErrServeTimeout wrapped by a TimeoutError when the Serve stage runs past its timeout
+	ErrServeTimeout = errors.New("serve stage timed out")
+	// ErrDeadlineTooShort wrapped by a TimeoutError when the request deadline leaves less than the minimum required to serve
+	ErrDeadlineTooShort = errors.New("not enough time left before the deadline to serve")
 )
 
+// ErrorDomain is the domain set on the google.rpc.ErrorInfo detail of statuses produced by ToGrpcStatus
+const ErrorDomain = "resdes"
+
+const customValidationErrKey = "custom_validation_error"
+
+const (
+	// internalReason the ErrorInfo reason of statuses converted from an InternalError
+	internalReason = "INTERNAL"
+	// timeoutReason the ErrorInfo reason of statuses converted from a TimeoutError
+	timeoutReason = "TIMEOUT"
+	// stageKey the ErrorInfo metadata key of the stage of an InternalError or TimeoutError
+	stageKey = "stage"
+	// timeoutKey the ErrorInfo metadata key of the timeout of a TimeoutError
+	timeoutKey = "timeout"
+	// attemptsKey the ErrorInfo metadata key of the attempts of a ServeErr
+	attemptsKey = "serve_attempts"
+)
+
+// customValidationErrMetadataKey returns the ErrorInfo metadata key of the i-th custom validation error
+func customValidationErrMetadataKey(i int) string {
+	if i == 0 {
+		return customValidationErrKey
+	}
+	return fmt.Sprintf("%s.%d", customValidationErrKey, i)
+}
+
+// fieldErrSentinels are the policy errors that can be recovered from a field violation description
+var fieldErrSentinels = []error{
+	ErrFieldComparisonFailedNotComparable,
+	ErrFieldMustEqualFailed,
+	ErrFieldMustNotEqualFailed,
+	ErrFieldMustNotBeZeroFailed,
+	ErrFieldLengthTooShort,
+	ErrFieldLengthTooLong,
+	ErrFieldPatternMismatch,
+	ErrFieldMissingPrefix,
+	ErrFieldMissingSuffix,
+	ErrFieldMissingSubstring,
+	ErrFieldInvalidEmail,
+	ErrFieldInvalidHostname,
+	ErrFieldInvalidIP,
+	ErrFieldInvalidURI,
+	ErrFieldInvalidUUID,
+	ErrFieldMustBeGreaterThan,
+	ErrFieldMustBeGreaterOrEqual,
+	ErrFieldMustBeLessThan,
+	ErrFieldMustBeLessOrEqual,
+	ErrFieldOutOfRange,
+	ErrFieldEnumUndefined,
+	ErrFieldEnumUnspecified,
+	ErrFieldEnumNotAllowed,
+	ErrFieldOneofNotSet,
+	ErrFieldOneofMultipleSet,
+	ErrFieldForbidden,
+	ErrFieldInvalidTimestamp,
+	ErrFieldTimestampNotInPast,
+	ErrFieldTimestampNotInFuture,
+	ErrFieldTimestampOutsideWindow,
+	ErrFieldTimestampNotBefore,
+	ErrFieldTimestampNotAfter,
+	ErrFieldInvalidDuration,
+	ErrFieldDurationTooShort,
+	ErrFieldDurationTooLong,
+	ErrFieldPathNotFound,
+	ErrFieldMaskPathThroughRepeated,
+}
+
+// remoteError is an error decoded from a gRPC status. It keeps the
+// original message and unwraps to any errors that could be recovered
+type remoteError struct {
+	msg  string
+	errs []error
+}
+
+func newRemoteError(msg string, errs ...error) error {
+	return &remoteError{
+		msg:  msg,
+		errs: errs,
+	}
+}
+
+func (r *remoteError) Error() string {
+	return r.msg
+}
+
+func (r *remoteError) Unwrap() []error {
+	return r.errs
+}
+
 func newFieldsNotComparableErr(id string, exp reflect.Type, act reflect.Type) error {
 	return fmt.Errorf("field: %s, value: %v, compareTo: %v: %w", id, exp, act, ErrFieldComparisonFailedNotComparable)
 }
@@ -36,6 +209,66 @@ func newFieldMustNotBeZeroFailedErr(id string, act any) error {
 	return fmt.Errorf("field: %s, value: %v: %w", id, act, ErrFieldMustNotBeZeroFailed)
 }
 
+func newFieldLengthTooShortErr(id string, act any, min any) error {
+	return fmt.Errorf("field: %s, value: %v, min: %v: %w", id, act, min, ErrFieldLengthTooShort)
+}
+
+func newFieldLengthTooLongErr(id string, act any, max any) error {
+	return fmt.Errorf("field: %s, value: %v, max: %v: %w", id, act, max, ErrFieldLengthTooLong)
+}
+
+func newFieldStringConstraintErr(id string, act any, constraint any, err error) error {
+	return fmt.Errorf("field: %s, value: %v, constraint: %v: %w", id, act, constraint, err)
+}
+
+func newFieldFormatErr(id string, act any, err error) error {
+	return fmt.Errorf("field: %s, value: %v: %w", id, act, err)
+}
+
+func newFieldBoundErr(id string, act any, bound any, err error) error {
+	return fmt.Errorf("field: %s, value: %v, bound: %v: %w", id, act, bound, err)
+}
+
+func newFieldEnumErr(id string, act string, err error) error {
+	return fmt.Errorf("field: %s, value: %s: %w", id, act, err)
+}
+
+func newFieldEnumNotAllowedErr(id string, act string, allowed []string) error {
+	return fmt.Errorf("field: %s, value: %s, allowed: %v: %w", id, act, allowed, ErrFieldEnumNotAllowed)
+}
+
+func newFieldOneofErr(id string, set []string, err error) error {
+	return fmt.Errorf("field: %s, set: %v: %w", id, set, err)
+}
+
+func newFieldForbiddenErr(id string, act any) error {
+	return fmt.Errorf("field: %s, value: %v: %w", id, act, ErrFieldForbidden)
+}
+
+func newFieldTriggeredErr(trigger string, err error) error {
+	return fmt.Errorf("triggered by: %s: %w", trigger, err)
+}
+
+func newFieldTimeErr(id string, act any, err error) error {
+	return fmt.Errorf("field: %s, value: %v: %w", id, act, err)
+}
+
+func newFieldTimeBoundErr(id string, act any, bound any, err error) error {
+	return fmt.Errorf("field: %s, value: %v, bound: %v: %w", id, act, bound, err)
+}
+
+func newFieldTimeOtherErr(id string, act any, other string, otherAct any, err error) error {
+	return fmt.Errorf("field: %s, value: %v, other: %s, other value: %v: %w", id, act, other, otherAct, err)
+}
+
+func newFieldMaskPathErr(id string, path string, segment string, err error) error {
+	return fmt.Errorf("field: %s, path: %s, segment: %s: %w", id, path, segment, err)
+}
+
+func newFieldPathNotFoundErr(id string, segment string) error {
+	return fmt.Errorf("field: %s, segment: %s: %w", id, segment, ErrFieldPathNotFound)
+}
+
 // AuthError wraps when an error occurs in the auth stage
 type AuthError struct {
 	Err error
@@ -58,6 +291,8 @@ func (a *AuthError) Unwrap() error {
 // ServeErr wraps when an error occurs during the handle stage
 type ServeErr struct {
 	Err error
+	// Attempts the number of times Serve was attempted, when the arrangement has a retry policy
+	Attempts int
 }
 
 func NewServeError(err error) *ServeErr {
@@ -74,11 +309,109 @@ func (h *ServeErr) Unwrap() error {
 	return h.Err
 }
 
+func (h *ServeErr) GetAttempts() int {
+	if h == nil {
+		return 0
+	}
+	return h.Attempts
+}
+
+// InternalError wraps a panic recovered from a stage of an arrangement, or from the arrangement
+// itself (NoStage). The panic value and stack are kept for logging and are never sent to clients
+type InternalError struct {
+	Stage Stage
+	Value any
+	Stack []byte
+}
+
+// NewInternalError creates an InternalError for the panic value, capturing the current stack.
+// Call it from the deferred function that recovered the panic
+func NewInternalError(stage Stage, value any) *InternalError {
+	return &InternalError{
+		Stage: stage,
+		Value: value,
+		Stack: debug.Stack(),
+	}
+}
+
+func (i *InternalError) Error() string {
+	return fmt.Sprintf("panic in %s stage: %v", i.Stage, i.Value)
+}
+
+// Unwrap returns the panic value if it is an error
+func (i *InternalError) Unwrap() error {
+	if err, ok := i.Value.(error); ok {
+		return err
+	}
+	return nil
+}
+
+// TimeoutError returned when a stage runs past its timeout, or when the request deadline
+// leaves less than the minimum required to start the Serve stage
+type TimeoutError struct {
+	Stage Stage
+	// Timeout the timeout of the stage, or the minimum remaining deadline
+	Timeout time.Duration
+	// Err the timeout sentinel of the stage, or ErrDeadlineTooShort
+	Err error
+}
+
+// NewTimeoutError creates a TimeoutError for a stage that ran past its timeout
+func NewTimeoutError(stage Stage, timeout time.Duration) *TimeoutError {
+	return &TimeoutError{
+		Stage:   stage,
+		Timeout: timeout,
+		Err:     stageTimeoutErr(stage),
+	}
+}
+
+func (t *TimeoutError) Error() string {
+	if errors.Is(t.Err, ErrDeadlineTooShort) {
+		return fmt.Sprintf("%v: %s required", t.Err, t.Timeout)
+	}
+	return fmt.Sprintf("%v after %s", t.Err, t.Timeout)
+}
+
+// Unwrap returns the sentinel of the timeout and context.DeadlineExceeded
+func (t *TimeoutError) Unwrap() []error {
+	return []error{t.Err, context.DeadlineExceeded}
+}
+
+// stageTimeoutErr returns the timeout sentinel of the stage
+func stageTimeoutErr(stage Stage) error {
+	switch stage {
+	case AuthStage:
+		return ErrAuthTimeout
+	case ValidateStage:
+		return ErrValidateTimeout
+	case ServeStage:
+		return ErrServeTimeout
+	}
+	return context.DeadlineExceeded
+}
+
+// ResponseValidationError returned when the result of Serve fails the ValidateResponse stage.
+// It is a failure of the server, never of the caller
+type ResponseValidationError struct {
+	Errs *ValidationErrors
+}
+
+func (r *ResponseValidationError) Error() string {
+	return fmt.Sprintf("response validation failed: %v", r.Errs)
+}
+
+func (r *ResponseValidationError) Unwrap() error {
+	return r.Errs
+}
+
 // Error holds errors for each stage of a request
 type Error struct {
-	AuthError      *AuthError
-	ValidationErrs *ValidationErrors
-	ServeError     *ServeErr
+	AuthError             *AuthError
+	ValidationErrs        *ValidationErrors
+	ServeError            *ServeErr
+	InternalError         *InternalError
+	TimeoutError          *TimeoutError
+	ResponseValidationErr *ResponseValidationError
 }
 
 func NewError(errs ...error) *Error {
@@ -104,12 +437,18 @@ func NewError(errs ...error) *Error {
 
 func (e *Error) Unwrap() error {
 	switch {
+	case e.GetInternalError() != nil:
+		return e.GetInternalError()
+	case e.GetTimeoutError() != nil:
+		return e.GetTimeoutError()
 	case e.GetAuthError() != nil:
 		return e.GetAuthError()
 	case e.GetValidationErrors() != nil:
 		return e.GetValidationErrors()
 	case e.GetServeError() != nil:
 		return e.GetServeError()
+	case e.GetResponseValidationError() != nil:
+		return e.GetResponseValidationError()
 	}
 	return nil
 }
@@ -135,6 +474,48 @@ func (e *Error) SetServeError(err error) {
 	e.ServeError = NewServeError(err)
 }
 
+func (e *Error) SetInternalError(err *InternalError) {
+	if e == nil {
+		e = &Error{}
+	}
+	e.InternalError = err
+}
+
+func (e *Error) GetInternalError() *InternalError {
+	if e == nil {
+		return nil
+	}
+	return e.InternalError
+}
+
+func (e *Error) SetResponseValidationError(err *ResponseValidationError) {
+	if e == nil {
+		e = &Error{}
+	}
+	e.ResponseValidationErr = err
+}
+
+func (e *Error) GetResponseValidationError() *ResponseValidationError {
+	if e == nil {
+		return nil
+	}
+	return e.ResponseValidationErr
+}
+
+func (e *Error) SetTimeoutError(err *TimeoutError) {
+	if e == nil {
+		e = &Error{}
+	}
+	e.TimeoutError = err
+}
+
+func (e *Error) GetTimeoutError() *TimeoutError {
+	if e == nil {
+		return nil
+	}
+	return e.TimeoutError
+}
+
 func (e *Error) GetAuthError() *AuthError {
 	if e == nil {
 		return nil
@@ -156,18 +537,303 @@ func (e *Error) GetServeError() *ServeErr {
 	return e.ServeError
 }
 
-func (e *Error) ToGrpcStatus() *status.Status {
-	return nil
+// GrpcStatusOption configures how an Error is converted into a gRPC status
+type GrpcStatusOption func(*grpcStatusConfig)
+
+type grpcStatusConfig struct {
+	authCode  codes.Code
+	serveCode codes.Code
+}
+
+// WithAuthCode sets the code used for auth errors that do not wrap
+// ErrUnauthenticated, ErrPermissionDenied or a gRPC status. Defaults to codes.Unauthenticated
+func WithAuthCode(code codes.Code) GrpcStatusOption {
+	return func(c *grpcStatusConfig) {
+		c.authCode = code
+	}
+}
+
+// WithServeCode sets the code used for serve errors that do not wrap
+// a gRPC status. Defaults to codes.Internal
+func WithServeCode(code codes.Code) GrpcStatusOption {
+	return func(c *grpcStatusConfig) {
+		c.serveCode = code
+	}
+}
+
+// ToGrpcStatus converts the error into a gRPC status:
+// - auth errors map to Unauthenticated or PermissionDenied
+// - validation errors map to InvalidArgument with a google.rpc.BadRequest detail
+// holding one field violation per field error
+// - serve errors keep the code of a wrapped gRPC status, otherwise use the configured serve code
+// - internal errors map to Internal, without the panic value or stack
+// - timeouts map to DeadlineExceeded
+// - response validation errors map to Internal, without the field errors
+//
+// Every status carries a google.rpc.ErrorInfo detail in the resdes domain naming the
+// failed stage so that FromGrpcStatus can rebuild the error on the client.
+// Returns nil (an OK status) if no error is set
+func (e *Error) ToGrpcStatus(options ...GrpcStatusOption) *status.Status {
+	cfg := &grpcStatusConfig{
+		authCode:  codes.Unauthenticated,
+		serveCode: codes.Internal,
+	}
+	for _, o := range options {
+		o(cfg)
+	}
+	var s *status.Status
+	switch {
+	case e.GetInternalError() != nil:
+		return internalErrorToStatus(e.GetInternalError())
+	case e.GetTimeoutError() != nil:
+		return timeoutErrorToStatus(e.GetTimeoutError())
+	case e.GetAuthError() != nil:
+		s = authErrorToStatus(e.GetAuthError(), cfg.authCode)
+	case e.GetValidationErrors() != nil:
+		s = e.GetValidationErrors().toStatus()
+	case e.GetServeError() != nil:
+		s = serveErrorToStatus(e.GetServeError(), cfg.serveCode)
+	case e.GetResponseValidationError() != nil:
+		s = status.New(codes.Internal, "resdes: invalid response")
+	default:
+		return nil
+	}
+	info := &errdetails.ErrorInfo{
+		Reason: e.Stage().Reason(),
+		Domain: ErrorDomain,
+	}
+	if cves := e.GetValidationErrors().GetCustomValidationErrs(); len(cves) > 0 {
+		info.Metadata = make(map[string]string, len(cves))
+		for i, cve := range cves {
+			info.Metadata[customValidationErrMetadataKey(i)] = cve.Error()
+		}
+	}
+	if attempts := e.GetServeError().GetAttempts(); attempts > 0 {
+		info.Metadata = map[string]string{attemptsKey: strconv.Itoa(attempts)}
+	}
+	if ds, err := s.WithDetails(info); err == nil {
+		return ds
+	}
+	return s
+}
+
+// GRPCStatus allows the error to be returned directly from a gRPC handler.
+// Use ToGrpcStatus to configure the conversion
+func (e *Error) GRPCStatus() *status.Status {
+	return e.ToGrpcStatus()
+}
+
+// Stage returns the stage of the arrangement that produced the error
+func (e *Error) Stage() Stage {
+	switch {
+	case e.GetInternalError() != nil:
+		return e.GetInternalError().Stage
+	case e.GetTimeoutError() != nil:
+		return e.GetTimeoutError().Stage
+	case e.GetAuthError() != nil:
+		return AuthStage
+	case e.GetValidationErrors() != nil:
+		return ValidateStage
+	case e.GetServeError() != nil:
+		return ServeStage
+	case e.GetResponseValidationError() != nil:
+		return ValidateResponseStage
+	}
+	return NoStage
+}
+
+// FromError rebuilds an Error from an error returned by a gRPC client call.
+// If the error is not a gRPC status, it is treated as a serve error
+func FromError(err error) *Error {
+	if err == nil {
+		return nil
+	}
+	var e *Error
+	if errors.As(err, &e) {
+		return e
+	}
+	s, ok := status.FromError(err)
+	if !ok {
+		return &Error{
+			ServeError: NewServeError(err),
+		}
+	}
+	return FromGrpcStatus(s)
+}
+
+// FromGrpcStatus rebuilds an Error from a status produced by ToGrpcStatus.
+// Field violations are decoded into FieldErrors so that the paths, policies and
+// messages are available and errors.Is matches the original policy sentinel errors.
+// Statuses produced elsewhere are classified by their code. Returns nil for an OK status
+func FromGrpcStatus(s *status.Status) *Error {
+	if s == nil || s.Code() == codes.OK {
+		return nil
+	}
+	var (
+		info *errdetails.ErrorInfo
+		br   *errdetails.BadRequest
+	)
+	for _, d := range s.Details() {
+		switch d := d.(type) {
+		case *errdetails.ErrorInfo:
+			if d.GetDomain() == ErrorDomain {
+				info = d
+			}
+		case *errdetails.BadRequest:
+			br = d
+		}
+	}
+	if info.GetReason() == internalReason {
+		return &Error{
+			InternalError: &InternalError{
+				Stage: stageFromString(info.GetMetadata()[stageKey]),
+				Value: s.Message(),
+			},
+		}
+	}
+	if info.GetReason() == timeoutReason {
+		return &Error{TimeoutError: timeoutErrorFromInfo(s, info)}
+	}
+	stage := stageFromReason(info.GetReason())
+	if stage == NoStage {
+		stage = stageFromCode(s.Code(), br)
+	}
+	switch stage {
+	case AuthStage:
+		errs := []error{s.Err()}
+		switch s.Code() {
+		case codes.PermissionDenied:
+			errs = append(errs, ErrPermissionDenied)
+		case codes.Unauthenticated:
+			errs = append(errs, ErrUnauthenticated)
+		}
+		return &Error{
+			AuthError: NewAuthError(newRemoteError(s.Message(), errs...)),
+		}
+	case ValidateStage:
+		return &Error{
+			ValidationErrs: validationErrorsFromStatus(s, br, info),
+		}
+	case ValidateResponseStage:
+		errs := NewValidationErrors()
+		errs.AddCustomValidationErr(newRemoteError(s.Message(), s.Err()))
+		return &Error{
+			ResponseValidationErr: &ResponseValidationError{Errs: errs},
+		}
+	default:
+		se := NewServeError(s.Err())
+		se.Attempts, _ = strconv.Atoi(info.GetMetadata()[attemptsKey])
+		return &Error{
+			ServeError: se,
+		}
+	}
+}
+
+func stageFromCode(code codes.Code, br *errdetails.BadRequest) Stage {
+	switch {
+	case code == codes.Unauthenticated || code == codes.PermissionDenied:
+		return AuthStage
+	case code == codes.InvalidArgument && br != nil:
+		return ValidateStage
+	}
+	return ServeStage
+}
+
+func validationErrorsFromStatus(s *status.Status, br *errdetails.BadRequest, info *errdetails.ErrorInfo) *ValidationErrors {
+	ve := NewValidationErrors()
+	for _, fv := range br.GetFieldViolations() {
+		ve.addErr(FieldErrorFromViolation(fv))
+	}
+	for i := 0; ; i++ {
+		cve, ok := info.GetMetadata()[customValidationErrMetadataKey(i)]
+		if !ok {
+			break
+		}
+		ve.AddCustomValidationErr(errors.New(cve))
+	}
+	if !ve.HasErrors() {
+		ve.AddCustomValidationErr(errors.New(s.Message()))
+	}
+	return ve
+}
+
+// internalErrorToStatus converts a recovered panic into an Internal status naming only the stage
+func internalErrorToStatus(err *InternalError) *status.Status {
+	s := status.New(codes.Internal, fmt.Sprintf("resdes: internal error in %s stage", err.Stage))
+	ds, derr := s.WithDetails(&errdetails.ErrorInfo{
+		Reason:   internalReason,
+		Domain:   ErrorDomain,
+		Metadata: map[string]string{stageKey: err.Stage.String()},
+	})
+	if derr != nil {
+		return s
+	}
+	return ds
+}
+
+// timeoutErrorToStatus converts a timeout into a DeadlineExceeded status naming the stage and timeout
+func timeoutErrorToStatus(err *TimeoutError) *status.Status {
+	s := status.New(codes.DeadlineExceeded, err.Error())
+	ds, derr := s.WithDetails(&errdetails.ErrorInfo{
+		Reason: timeoutReason,
+		Domain: ErrorDomain,
+		Metadata: map[string]string{
+			stageKey:   err.Stage.String(),
+			timeoutKey: err.Timeout.String(),
+		},
+	})
+	if derr != nil {
+		return s
+	}
+	return ds
+}
+
+// timeoutErrorFromInfo rebuilds a TimeoutError from the status and its ErrorInfo detail
+func timeoutErrorFromInfo(s *status.Status, info *errdetails.ErrorInfo) *TimeoutError {
+	timeout, _ := time.ParseDuration(info.GetMetadata()[timeoutKey])
+	terr := NewTimeoutError(stageFromString(info.GetMetadata()[stageKey]), timeout)
+	if strings.HasPrefix(s.Message(), ErrDeadlineTooShort.Error()) {
+		terr.Err = ErrDeadlineTooShort
+	}
+	return terr
+}
+
+func authErrorToStatus(err *AuthError, fallback codes.Code) *status.Status {
+	if s, ok := status.FromError(err.Err); ok && s != nil {
+		if s.Code() == codes.Unauthenticated || s.Code() == codes.PermissionDenied {
+			return s
+		}
+	}
+	switch {
+	case errors.Is(err, ErrPermissionDenied):
+		return status.New(codes.PermissionDenied, err.Error())
+	case errors.Is(err, ErrUnauthenticated):
+		return status.New(codes.Unauthenticated, err.Error())
+	}
+	return status.New(fallback, err.Error())
+}
+
+func serveErrorToStatus(err *ServeErr, fallback codes.Code) *status.Status {
+	if s, ok := status.FromError(err.Err); ok && s != nil {
+		return s
+	}
+	return status.New(fallback, err.Error())
 }
 
 func (e *Error) Error() string {
 	switch {
+	case e.GetInternalError() != nil:
+		return e.GetInternalError().Error()
+	case e.GetTimeoutError() != nil:
+		return e.GetTimeoutError().Error()
 	case e.GetAuthError() != nil:
 		return e.GetAuthError().Error()
 	case e.GetValidationErrors() != nil:
 		return e.GetValidationErrors().Error()
 	case e.GetServeError() != nil:
 		return e.GetServeError().Error()
+	case e.GetResponseValidationError() != nil:
+		return e.GetResponseValidationError().Error()
 	}
 	return ""
 }
@@ -186,11 +852,30 @@ func WithExpectedValue(value any) AddFieldValidationErrOption {
 	}
 }
 
+// CustomValidationError an error returned by a custom validation function, tagged with its name
+type CustomValidationError struct {
+	Validator string
+	Err       error
+}
+
+func (e *CustomValidationError) Error() string {
+	if e.Validator == "" {
+		return fmt.Sprintf("an error occurred during custom message validation: %v", e.Err)
+	}
+	return fmt.Sprintf("an error occurred during custom message validation %s: %v", e.Validator, e.Err)
+}
+
+func (e *CustomValidationError) Unwrap() error {
+	return e.Err
+}
+
 // ValidationErrors holds faults for each field evaluated
 type ValidationErrors struct {
-	FieldErrors           []*FieldError
-	CustomValidationError error
-	idx                   map[string]int
+	FieldErrors            []*FieldError
+	CustomValidationErrors []error
+	idx                    map[fieldErrKey]int
+	// name of the custom validation currently running, tagged on the field errors it adds
+	validator string
 }
 
 func ValidationErrorsFromErr(err error) *ValidationErrors {
@@ -204,7 +889,7 @@ func ValidationErrorsFromErr(err error) *ValidationErrors {
 func NewValidationErrors() *ValidationErrors {
 	return &ValidationErrors{
 		FieldErrors: []*FieldError{},
-		idx:         make(map[string]int),
+		idx:         make(map[fieldErrKey]int),
 	}
 }
 
@@ -215,9 +900,10 @@ func (v *ValidationErrors) addFieldErr(field *Field, err error) {
 // AddFieldErr adds an error for a field from a custom eval function
 func (v *ValidationErrors) AddFieldErr(path string, err error, options ...AddFieldValidationErrOption) {
 	fe := &FieldError{
-		Path:   path,
-		Err:    err,
-		Policy: Custom,
+		Path:      path,
+		Err:       err,
+		Policy:    Custom,
+		Validator: v.validator,
 	}
 	if len(options) > 0 {
 		for _, o := range options {
@@ -236,24 +922,54 @@ func (v ValidationErrors) Paths() []string {
 }
 
 func (v ValidationErrors) HasErrors() bool {
-	return len(v.FieldErrors) > 0 || v.CustomValidationError != nil
+	return len(v.FieldErrors) > 0 || len(v.CustomValidationErrors) > 0
+}
+
+// fieldErrKey identifies the field error that errors on the same path are merged into.
+// Errors added by different custom validations are kept apart so each keeps its validator name
+type fieldErrKey struct {
+	validator string
+	path      string
 }
 
 func (v *ValidationErrors) addErr(fieldErr *FieldError) {
 	if v.idx == nil {
-		v.idx = make(map[string]int)
+		v.idx = make(map[fieldErrKey]int)
 	}
-	idx, ok := v.idx[fieldErr.Path]
+	key := fieldErrKey{validator: fieldErr.Validator, path: fieldErr.Path}
+	idx, ok := v.idx[key]
 	if ok {
 		v.FieldErrors[idx].Err = errors.Join(v.FieldErrors[idx].Err, fieldErr.Err)
 	} else {
 		v.FieldErrors = append(v.FieldErrors, fieldErr)
-		v.idx[fieldErr.Path] = len(v.FieldErrors) - 1
+		v.idx[key] = len(v.FieldErrors) - 1
+	}
+}
+
+// AddCustomValidationErr adds a non-field error from a custom validation
+func (v *ValidationErrors) AddCustomValidationErr(err error) {
+	v.CustomValidationErrors = append(v.CustomValidationErrors, err)
+}
+
+// SetCustomValidationErr replaces the non-field errors from custom validations
+func (v *ValidationErrors) SetCustomValidationErr(errs ...error) {
+	v.CustomValidationErrors = errs
+}
+
+// GetCustomValidationErrs returns the non-field errors from custom validations, in the order they were added
+func (v *ValidationErrors) GetCustomValidationErrs() []error {
+	if v == nil {
+		return nil
 	}
+	return v.CustomValidationErrors
 }
 
-func (v *ValidationErrors) SetCustomValidationErr(err error) {
-	v.CustomValidationError = err
+// GetCustomValidationErr returns the non-field errors from custom validations joined into one error
+func (v *ValidationErrors) GetCustomValidationErr() error {
+	if v == nil {
+		return nil
+	}
+	return errors.Join(v.CustomValidationErrors...)
 }
 
 func (v *ValidationErrors) Error() string {
@@ -261,8 +977,8 @@ func (v *ValidationErrors) Error() string {
 		return ""
 	}
 	var out strings.Builder
-	if v.CustomValidationError != nil {
-		out.WriteString(v.CustomValidationError.Error() + "\n")
+	for _, e := range v.CustomValidationErrors {
+		out.WriteString(e.Error() + "\n")
 	}
 	for _, e := range v.FieldErrors {
 		out.WriteString(e.Error() + "\n")
@@ -270,22 +986,53 @@ func (v *ValidationErrors) Error() string {
 	return out.String()
 }
 
+// FieldViolations converts each field error into a google.rpc.BadRequest field violation
+func (v *ValidationErrors) FieldViolations() []*errdetails.BadRequest_FieldViolation {
+	if v == nil {
+		return nil
+	}
+	violations := make([]*errdetails.BadRequest_FieldViolation, 0, len(v.FieldErrors))
+	for _, e := range v.FieldErrors {
+		violations = append(violations, e.FieldViolation())
+	}
+	return violations
+}
+
+func (v *ValidationErrors) toStatus() *status.Status {
+	s := status.New(codes.InvalidArgument, strings.TrimSuffix(v.Error(), "\n"))
+	if len(v.FieldErrors) == 0 {
+		return s
+	}
+	ds, err := s.WithDetails(&errdetails.BadRequest{
+		FieldViolations: v.FieldViolations(),
+	})
+	if err != nil {
+		return s
+	}
+	return ds
+}
+
+// AsMap returns the field errors keyed by path. A path with errors from several
+// custom validations maps to the first of them
 func (v *ValidationErrors) AsMap() map[string]*FieldError {
 	if v == nil {
 		return nil
 	}
 	m := make(map[string]*FieldError)
 	for _, e := range v.FieldErrors {
-		m[e.Path] = e
+		if _, ok := m[e.Path]; !ok {
+			m[e.Path] = e
+		}
 	}
 	return m
 }
 
 func (v *ValidationErrors) Unwrap() error {
-	if len(v.FieldErrors) == 0 {
+	if !v.HasErrors() {
 		return nil
 	}
-	errs := make([]error, 0, len(v.FieldErrors))
+	errs := make([]error, 0, len(v.CustomValidationErrors)+len(v.FieldErrors))
+	errs = append(errs, v.CustomValidationErrors...)
 	for _, e := range v.FieldErrors {
 		errs = append(errs, e.Err)
 	}
@@ -297,7 +1044,11 @@ type FieldError struct {
 	Policy   Policy
 	Value    any
 	Expected any
-	Err      error
+	// path of the field that triggered a conditional rule, if any
+	Trigger string
+	// name of the custom validation that added the error, if any
+	Validator string
+	Err       error
 }
 
 func FieldErrorFromField(f *Field, err error) *FieldError {
@@ -306,6 +1057,7 @@ func FieldErrorFromField(f *Field, err error) *FieldError {
 		Policy:   f.Policy(),
 		Value:    f.Value(),
 		Expected: f.CompareTo(),
+		Trigger:  f.Trigger(),
 		Err:      err,
 	}
 }
@@ -317,6 +1069,35 @@ func (f *FieldError) Error() string {
 	return ""
 }
 
+// FieldViolation converts the field error into a google.rpc.BadRequest field violation
+func (f *FieldError) FieldViolation() *errdetails.BadRequest_FieldViolation {
+	fv := &errdetails.BadRequest_FieldViolation{
+		Field:  f.Path,
+		Reason: f.Policy.Reason(),
+	}
+	if f.Err != nil {
+		fv.Description = f.Err.Error()
+	}
+	return fv
+}
+
+// FieldErrorFromViolation rebuilds a field error from a google.rpc.BadRequest field violation.
+// The value and expected value are not available remotely
+func FieldErrorFromViolation(fv *errdetails.BadRequest_FieldViolation) *FieldError {
+	var errs []error
+	for _, sentinel := range fieldErrSentinels {
+		if strings.HasSuffix(fv.GetDescription(), sentinel.Error()) {
+			errs = append(errs, sentinel)
+			break
+		}
+	}
+	return &FieldError{
+		Path:   fv.GetField(),
+		Policy: policyFromReason(fv.GetReason()),
+		Err:    newRemoteError(fv.GetDescription(), errs...),
+	}
+}
+
 func (f *FieldError) Unwrap() error {
 	return f.Err
 }
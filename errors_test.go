@@ -0,0 +1,192 @@
+package resdes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	v1 "github.com/signal426/resdes/test_protos/gen/test_protos/resdes/v1"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGrpcStatus(t *testing.T) {
+	t.Run("it should map auth errors to unauthenticated by default", func(t *testing.T) {
+		// arrange
+		err := &Error{}
+		err.SetAuthError(errors.New("caller id cannot be empty"))
+
+		// act
+		s := err.ToGrpcStatus()
+
+		// assert
+		assert.Equal(t, codes.Unauthenticated, s.Code())
+		assert.Equal(t, "caller id cannot be empty", s.Message())
+	})
+
+	t.Run("it should map permission denied auth errors", func(t *testing.T) {
+		// arrange
+		err := &Error{}
+		err.SetAuthError(fmt.Errorf("caller cannot update user: %w", ErrPermissionDenied))
+
+		// act
+		s := err.ToGrpcStatus()
+
+		// assert
+		assert.Equal(t, codes.PermissionDenied, s.Code())
+	})
+
+	t.Run("it should map validation errors to bad request field violations", func(t *testing.T) {
+		// arrange
+		req := &v1.CreateUserRequest{
+			User: &v1.User{},
+		}
+		_, err := Arrange[*v1.CreateUserRequest, *v1.CreateUserResponse]().
+			WithValidate(ForMessage[*v1.CreateUserRequest]().
+				AssertNonZero("user.id", req.GetUser().GetId()).
+				AssertNotEqualTo("user.first_name", req.GetUser().GetFirstName(), "")).
+			Exec(context.Background(), req)
+
+		// act
+		s := err.ToGrpcStatus()
+
+		// assert
+		assert.Equal(t, codes.InvalidArgument, s.Code())
+		assert.Len(t, s.Details(), 2)
+		br, ok := s.Details()[0].(*errdetails.BadRequest)
+		assert.True(t, ok)
+		info, ok := s.Details()[1].(*errdetails.ErrorInfo)
+		assert.True(t, ok)
+		assert.Equal(t, ErrorDomain, info.GetDomain())
+		assert.Equal(t, "VALIDATION_FAILED", info.GetReason())
+		assert.Len(t, br.GetFieldViolations(), 2)
+		assert.Equal(t, "user.id", br.GetFieldViolations()[0].GetField())
+		assert.Equal(t, "NON_ZERO", br.GetFieldViolations()[0].GetReason())
+		assert.Equal(t, newFieldMustNotBeZeroFailedErr("user.id", "").Error(), br.GetFieldViolations()[0].GetDescription())
+		assert.Equal(t, "user.first_name", br.GetFieldViolations()[1].GetField())
+		assert.Equal(t, "MUST_NOT_EQUAL", br.GetFieldViolations()[1].GetReason())
+	})
+
+	t.Run("it should use the configured serve code", func(t *testing.T) {
+		// arrange
+		err := &Error{}
+		err.SetServeError(errors.New("user not found"))
+
+		// act
+		def := err.ToGrpcStatus()
+		configured := err.ToGrpcStatus(WithServeCode(codes.NotFound))
+
+		// assert
+		assert.Equal(t, codes.Internal, def.Code())
+		assert.Equal(t, codes.NotFound, configured.Code())
+		assert.Equal(t, "user not found", configured.Message())
+	})
+
+	t.Run("it should keep the code of a wrapped status in serve errors", func(t *testing.T) {
+		// arrange
+		err := &Error{}
+		err.SetServeError(status.Error(codes.AlreadyExists, "user exists"))
+
+		// act
+		s := err.ToGrpcStatus(WithServeCode(codes.NotFound))
+
+		// assert
+		assert.Equal(t, codes.AlreadyExists, s.Code())
+	})
+
+	t.Run("it should return an ok status when there is no error", func(t *testing.T) {
+		// arrange
+		var err *Error
+
+		// act
+		s := err.ToGrpcStatus()
+
+		// assert
+		assert.Equal(t, codes.OK, s.Code())
+		assert.Nil(t, s.Err())
+	})
+}
+
+func TestFromGrpcStatus(t *testing.T) {
+	t.Run("it should round-trip validation errors", func(t *testing.T) {
+		// arrange
+		req := &v1.CreateUserRequest{
+			User: &v1.User{
+				FirstName: "bob",
+			},
+		}
+		_, serr := Arrange[*v1.CreateUserRequest, *v1.CreateUserResponse]().
+			WithValidate(ForMessage[*v1.CreateUserRequest]().
+				AssertNonZero("user.id", req.GetUser().GetId()).
+				AssertNotEqualTo("user.first_name", req.GetUser().GetFirstName(), "bob")).
+			Exec(context.Background(), req)
+
+		// act
+		err := FromError(serr.ToGrpcStatus().Err())
+
+		// assert
+		assert.Error(t, err)
+		assert.Equal(t, ValidateStage, err.Stage())
+		assert.Equal(t, serr.Error(), err.Error())
+		assert.Equal(t, []string{"user.id", "user.first_name"}, err.GetValidationErrors().Paths())
+		fes := err.GetValidationErrors().AsMap()
+		assert.Equal(t, NonZero, fes["user.id"].Policy)
+		assert.Equal(t, NotEqualTo, fes["user.first_name"].Policy)
+		assert.ErrorIs(t, fes["user.id"], ErrFieldMustNotBeZeroFailed)
+		assert.ErrorIs(t, err, ErrFieldMustNotBeZeroFailed)
+		assert.ErrorIs(t, err, ErrFieldMustNotEqualFailed)
+		assert.NotErrorIs(t, err, ErrFieldMustEqualFailed)
+	})
+
+	t.Run("it should round-trip custom validation errors", func(t *testing.T) {
+		// arrange
+		serr := &Error{}
+		ve := NewValidationErrors()
+		ve.AddFieldErr("user.id", errors.New("user id cannot be abc123"))
+		ve.SetCustomValidationErr(errors.New("lookup failed"), errors.New("quota exceeded"))
+		serr.SetValidationErrors(ve)
+
+		// act
+		err := FromGrpcStatus(serr.ToGrpcStatus())
+
+		// assert
+		assert.Equal(t, Custom, err.GetValidationErrors().AsMap()["user.id"].Policy)
+		assert.Len(t, err.GetValidationErrors().GetCustomValidationErrs(), 2)
+		assert.EqualError(t, err.GetValidationErrors().GetCustomValidationErr(), "lookup failed\nquota exceeded")
+	})
+
+	t.Run("it should round-trip auth errors", func(t *testing.T) {
+		// arrange
+		serr := &Error{}
+		serr.SetAuthError(fmt.Errorf("caller cannot update user: %w", ErrPermissionDenied))
+
+		// act
+		err := FromGrpcStatus(serr.ToGrpcStatus())
+
+		// assert
+		assert.NotNil(t, err.GetAuthError())
+		assert.Equal(t, "caller cannot update user: permission denied", err.Error())
+		assert.ErrorIs(t, err, ErrPermissionDenied)
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("it should classify statuses from other sources by code", func(t *testing.T) {
+		// act
+		auth := FromError(status.Error(codes.Unauthenticated, "no token"))
+		serve := FromError(status.Error(codes.InvalidArgument, "bad page token"))
+
+		// assert
+		assert.Equal(t, AuthStage, auth.Stage())
+		assert.Equal(t, ServeStage, serve.Stage())
+		assert.Equal(t, codes.InvalidArgument, status.Code(serve))
+	})
+
+	t.Run("it should return nil for ok statuses", func(t *testing.T) {
+		// assert
+		assert.Nil(t, FromGrpcStatus(status.New(codes.OK, "")))
+		assert.Nil(t, FromError(nil))
+	})
+}
@@ -2,8 +2,10 @@ package resdes
 
 import (
 	"reflect"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/reflect/protoreflect"
 )
 
 func isZero(i any) bool {
@@ -28,6 +30,13 @@ type Field struct {
 	policy         Policy
 	condition      Condition
 	cmpTo          any
+	// descriptors of the path, set if the value is resolved from the message
+	fds []protoreflect.FieldDescriptor
+	// predicate of a When condition, and the path of the field that triggered it
+	when    predicate
+	trigger string
+	// clock of time policies
+	now func() time.Time
 }
 
 func NewField(path string, value any, policy Policy, condition Condition, cmpTo any, paths map[string]struct{}) *Field {
@@ -44,15 +53,67 @@ func NewField(path string, value any, policy Policy, condition Condition, cmpTo
 	}
 }
 
+// newPathField creates a field whose value is resolved from the message when validated
+func newPathField(path string, fds []protoreflect.FieldDescriptor, policy Policy, condition Condition, cmpTo any, paths map[string]struct{}) *Field {
+	f := NewField(path, nil, policy, condition, cmpTo, paths)
+	f.fds = fds
+	return f
+}
+
+// resolve returns a copy of the field holding its value in the supplied message.
+// Fields created with a value are returned as-is
+func (f *Field) resolve(m protoreflect.Message) *Field {
+	if f.fds == nil {
+		return f
+	}
+	resolved := *f
+	resolved.value = valueAt(m, f.fds)
+	resolved.zero = isZero(resolved.value)
+	return &resolved
+}
+
+// validate resolves the field from the message and adds any error to errs
+func (f *Field) validate(m protoreflect.Message, errs *ValidationErrors) {
+	field := f.resolve(m)
+	if f.condition == When {
+		trigger, ok := f.when(m)
+		if !ok {
+			return
+		}
+		triggered := *field
+		triggered.trigger = trigger
+		field = &triggered
+	}
+	if err := field.Validate(); err != nil {
+		errs.addFieldErr(field, err)
+	}
+}
+
 func (f Field) Validate() error {
 	if f.condition == InMask && !f.inMask {
 		return nil
 	}
-	if f.policy == NonZero {
+	switch f.policy {
+	case NonZero:
 		if f.zero {
-			return newFieldMustNotBeZeroFailedErr(f.path, f.value)
+			return f.triggered(newFieldMustNotBeZeroFailedErr(f.path, f.value))
 		}
 		return nil
+	case Forbidden:
+		if !f.zero {
+			return f.triggered(newFieldForbiddenErr(f.path, f.value))
+		}
+		return nil
+	case MinLength, MaxLength, Pattern, Prefix, Suffix, Contains, Email, Hostname, IP, URI, UUID:
+		return f.checkString()
+	case GreaterThan, GreaterOrEqual, LessThan, LessOrEqual, Between:
+		return f.checkNumber()
+	case EnumDefined, EnumSpecified, EnumIn:
+		return f.checkEnum()
+	case OneofRequired, OneofExactlyOne, OneofAtMostOne:
+		return f.checkOneof()
+	case TimestampValid, TimestampPast, TimestampFuture, TimestampWithin, TimestampBefore, TimestampAfter, DurationMin, DurationMax:
+		return f.checkTime()
 	}
 	eq, err := f.checkEquals()
 	if err != nil {
@@ -98,6 +159,18 @@ func (f Field) ID() string {
 	return f.path
 }
 
+// Trigger returns the path of the field that triggered a conditional rule
+func (f Field) Trigger() string {
+	return f.trigger
+}
+
+func (f Field) triggered(err error) error {
+	if f.trigger == "" {
+		return err
+	}
+	return newFieldTriggeredErr(f.trigger, err)
+}
+
 func (f Field) InMask() bool {
 	return f.inMask
 }
@@ -108,5 +181,13 @@ func (f Field) checkEquals() (bool, error) {
 	if fieldCmpType != compareToType {
 		return false, newFieldsNotComparableErr(f.path, fieldCmpType, compareToType)
 	}
+	// scalars compare the same as with cmp.Equal, without its allocations
+	switch fieldCmpType.Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return f.value == f.cmpTo, nil
+	}
 	return cmp.Equal(f.value, f.cmpTo), nil
 }
@@ -0,0 +1,202 @@
+package resdes
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// unaryArrangement runs an arrangement for a unary RPC whose
+// request type is only known at runtime
+type unaryArrangement interface {
+	execUnary(ctx context.Context, req any, handler grpc.UnaryHandler, options []GrpcStatusOption) (any, error)
+}
+
+// ArrangementFunc builds an arrangement for an incoming request. Use it when
+// the validator depends on the values of the request
+type ArrangementFunc[T proto.Message, U any] func(context.Context, T) *Arrangement[T, U]
+
+func (f ArrangementFunc[T, U]) execUnary(ctx context.Context, req any, handler grpc.UnaryHandler, options []GrpcStatusOption) (any, error) {
+	msg, ok := req.(T)
+	if !ok {
+		return nil, status.Errorf(codes.Internal, "resdes: unexpected request type %T", req)
+	}
+	a := f(ctx, msg)
+	if a == nil {
+		return handler(ctx, req)
+	}
+
+	// the arrangement serves the request in place of the handler
+	if a.Serve != nil {
+		res, err := a.Exec(ctx, msg)
+		if err != nil {
+			return nil, err.ToGrpcStatus(options...).Err()
+		}
+		return res, nil
+	}
+
+	// otherwise the handler serves the request once the Auth and Validate stages have passed.
+	// Serve errors are returned as-is, as they would be without the interceptor
+	var handled any
+	res, serr := a.exec(ctx, msg, func(ctx context.Context, _ T) (U, error) {
+		var err error
+		handled, err = handler(ctx, req)
+		u, _ := handled.(U)
+		return u, err
+	})
+	if serr != nil {
+		if se := serr.GetServeError(); se != nil && se.Err != nil && !errors.As(se.Err, new(*Error)) {
+			return nil, se.Err
+		}
+		return nil, serr.ToGrpcStatus(options...).Err()
+	}
+	// the result is replayed without calling the handler for a retried idempotent request
+	if _, ok := handled.(U); ok || handled == nil {
+		return res, nil
+	}
+	return handled, nil
+}
+
+// streamArrangement runs an arrangement for a streaming RPC whose
+// message types are only known at runtime
+type streamArrangement interface {
+	execStream(srv any, ss grpc.ServerStream, handler grpc.StreamHandler, options []GrpcStatusOption) error
+}
+
+func (s *StreamArrangement[T, U]) execStream(srv any, ss grpc.ServerStream, handler grpc.StreamHandler, options []GrpcStatusOption) error {
+	// the arrangement serves the stream in place of the handler
+	if s.Serve != nil {
+		if err := s.Exec(&grpcStream[T, U]{ServerStream: ss}); err != nil {
+			return err.ToGrpcStatus(options...).Err()
+		}
+		return nil
+	}
+
+	if err := s.open(ss.Context()); err != nil {
+		return err.ToGrpcStatus(options...).Err()
+	}
+	return handler(srv, &guardedServerStream[T, U]{ServerStream: ss, arrangement: s, options: options})
+}
+
+// grpcStream adapts a grpc.ServerStream to a Stream
+type grpcStream[T proto.Message, U any] struct {
+	grpc.ServerStream
+}
+
+func (g *grpcStream[T, U]) Recv() (T, error) {
+	msg := newMessage[T]()
+	if err := g.RecvMsg(msg); err != nil {
+		var zero T
+		return zero, err
+	}
+	return msg, nil
+}
+
+func (g *grpcStream[T, U]) Send(res U) error {
+	return g.SendMsg(res)
+}
+
+// guardedServerStream runs the per-message stages of the arrangement
+// on every message received by a generated handler
+type guardedServerStream[T proto.Message, U any] struct {
+	grpc.ServerStream
+	arrangement *StreamArrangement[T, U]
+	options     []GrpcStatusOption
+}
+
+func (g *guardedServerStream[T, U]) RecvMsg(m any) error {
+	msg, ok := m.(T)
+	if !ok {
+		return status.Errorf(codes.Internal, "resdes: unexpected request type %T", m)
+	}
+	_, err := g.arrangement.recv(g.Context(), func() (T, error) {
+		return msg, g.ServerStream.RecvMsg(msg)
+	})
+	var serr *Error
+	if errors.As(err, &serr) {
+		return serr.ToGrpcStatus(g.options...).Err()
+	}
+	return err
+}
+
+func newMessage[T proto.Message]() T {
+	var zero T
+	return zero.ProtoReflect().Type().New().Interface().(T)
+}
+
+// Registry holds the arrangements to run for each gRPC method,
+// keyed by full method name (e.g. /resdes.v1.UserService/UpdateUser)
+type Registry struct {
+	mu      sync.RWMutex
+	unary   map[string]unaryArrangement
+	stream  map[string]streamArrangement
+	options []GrpcStatusOption
+}
+
+// NewRegistry creates a new Registry. The options are used to convert
+// arrangement errors into gRPC statuses
+func NewRegistry(options ...GrpcStatusOption) *Registry {
+	return &Registry{
+		unary:   make(map[string]unaryArrangement),
+		stream:  make(map[string]streamArrangement),
+		options: options,
+	}
+}
+
+// Register registers an arrangement to run for the supplied method. If the arrangement
+// has a Serve stage, it serves the request in place of the handler. Otherwise the handler
+// is called once the Auth and Validate stages have passed
+func Register[T proto.Message, U any](r *Registry, fullMethod string, a *Arrangement[T, U]) {
+	RegisterFunc(r, fullMethod, func(context.Context, T) *Arrangement[T, U] {
+		return a
+	})
+}
+
+// RegisterFunc same as Register, but builds the arrangement for every request
+func RegisterFunc[T proto.Message, U any](r *Registry, fullMethod string, build ArrangementFunc[T, U]) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.unary[fullMethod] = build
+}
+
+// RegisterStream registers a stream arrangement to run for the supplied method. If the arrangement
+// has a Serve stage, it serves the stream in place of the handler. Otherwise the handler is called
+// once the OpenAuth stage has passed, with Auth and Validate run on every message it receives
+func RegisterStream[T proto.Message, U any](r *Registry, fullMethod string, a *StreamArrangement[T, U]) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.stream[fullMethod] = a
+}
+
+// UnaryServerInterceptor runs the arrangement registered for the called method.
+// Methods without an arrangement are passed to the handler untouched
+func (r *Registry) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		r.mu.RLock()
+		a, ok := r.unary[info.FullMethod]
+		r.mu.RUnlock()
+		if !ok {
+			return handler(ctx, req)
+		}
+		return a.execUnary(ctx, req, handler, r.options)
+	}
+}
+
+// StreamServerInterceptor runs the stream arrangement registered for the called method.
+// Methods without an arrangement are passed to the handler untouched
+func (r *Registry) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		r.mu.RLock()
+		a, ok := r.stream[info.FullMethod]
+		r.mu.RUnlock()
+		if !ok {
+			return handler(srv, ss)
+		}
+		return a.execStream(srv, ss, handler, r.options)
+	}
+}
@@ -0,0 +1,360 @@
+package resdes
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+
+	v1 "github.com/signal426/resdes/test_protos/gen/test_protos/resdes/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const (
+	createUserMethod = "/resdes.v1.UserService/CreateUser"
+	updateUserMethod = "/resdes.v1.UserService/UpdateUser"
+	syncUsersMethod  = "/resdes.v1.UserService/SyncUsers"
+)
+
+type userServer interface {
+	CreateUser(context.Context, *v1.CreateUserRequest) (*v1.CreateUserResponse, error)
+	UpdateUser(context.Context, *v1.UpdateUserRequest) (*v1.UpdateUserResponse, error)
+	SyncUsers(grpc.BidiStreamingServer[v1.CreateUserRequest, v1.CreateUserResponse]) error
+}
+
+type testUserServer struct {
+	calls int
+}
+
+func (s *testUserServer) CreateUser(_ context.Context, req *v1.CreateUserRequest) (*v1.CreateUserResponse, error) {
+	s.calls++
+	return &v1.CreateUserResponse{User: req.GetUser()}, nil
+}
+
+func (s *testUserServer) UpdateUser(_ context.Context, req *v1.UpdateUserRequest) (*v1.UpdateUserResponse, error) {
+	s.calls++
+	return &v1.UpdateUserResponse{User: req.GetUser()}, nil
+}
+
+func (s *testUserServer) SyncUsers(stream grpc.BidiStreamingServer[v1.CreateUserRequest, v1.CreateUserResponse]) error {
+	for {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		s.calls++
+		if err := stream.Send(&v1.CreateUserResponse{User: req.GetUser()}); err != nil {
+			return err
+		}
+	}
+}
+
+func unaryTestHandler[T any](method string, call func(userServer, context.Context, *T) (any, error)) grpc.MethodHandler {
+	return func(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+		in := new(T)
+		if err := dec(in); err != nil {
+			return nil, err
+		}
+		handler := func(ctx context.Context, req any) (any, error) {
+			return call(srv.(userServer), ctx, req.(*T))
+		}
+		if interceptor == nil {
+			return handler(ctx, in)
+		}
+		return interceptor(ctx, in, &grpc.UnaryServerInfo{Server: srv, FullMethod: method}, handler)
+	}
+}
+
+var userServiceDesc = grpc.ServiceDesc{
+	ServiceName: "resdes.v1.UserService",
+	HandlerType: (*userServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateUser",
+			Handler: unaryTestHandler(createUserMethod, func(s userServer, ctx context.Context, req *v1.CreateUserRequest) (any, error) {
+				return s.CreateUser(ctx, req)
+			}),
+		},
+		{
+			MethodName: "UpdateUser",
+			Handler: unaryTestHandler(updateUserMethod, func(s userServer, ctx context.Context, req *v1.UpdateUserRequest) (any, error) {
+				return s.UpdateUser(ctx, req)
+			}),
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName: "SyncUsers",
+			Handler: func(srv any, stream grpc.ServerStream) error {
+				return srv.(userServer).SyncUsers(&grpc.GenericServerStream[v1.CreateUserRequest, v1.CreateUserResponse]{ServerStream: stream})
+			},
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+}
+
+func dialTestServer(t *testing.T, srv userServer, options ...grpc.ServerOption) *grpc.ClientConn {
+	t.Helper()
+	lis := bufconn.Listen(1024 * 1024)
+	s := grpc.NewServer(options...)
+	s.RegisterService(&userServiceDesc, srv)
+	go func() {
+		_ = s.Serve(lis)
+	}()
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+	return conn
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	t.Run("it should run auth and validate before the handler", func(t *testing.T) {
+		// arrange
+		srv := &testUserServer{}
+		registry := NewRegistry()
+		RegisterFunc(registry, createUserMethod, func(_ context.Context, req *v1.CreateUserRequest) *Arrangement[*v1.CreateUserRequest, *v1.CreateUserResponse] {
+			return Arrange[*v1.CreateUserRequest, *v1.CreateUserResponse]().
+				WithAuth(func(context.Context, *v1.CreateUserRequest) error {
+					return nil
+				}).
+				WithValidate(ForMessage[*v1.CreateUserRequest]().
+					AssertNonZero("user.id", req.GetUser().GetId()))
+		})
+		conn := dialTestServer(t, srv, grpc.UnaryInterceptor(registry.UnaryServerInterceptor()))
+
+		// act
+		err := conn.Invoke(context.Background(), createUserMethod, &v1.CreateUserRequest{User: &v1.User{}}, &v1.CreateUserResponse{})
+
+		// assert
+		assert.Error(t, err)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		assert.Equal(t, 0, srv.calls)
+		serr := FromError(err)
+		assert.Equal(t, []string{"user.id"}, serr.GetValidationErrors().Paths())
+		assert.ErrorIs(t, serr, ErrFieldMustNotBeZeroFailed)
+	})
+
+	t.Run("it should call the handler when the stages pass", func(t *testing.T) {
+		// arrange
+		srv := &testUserServer{}
+		registry := NewRegistry()
+		RegisterFunc(registry, createUserMethod, func(_ context.Context, req *v1.CreateUserRequest) *Arrangement[*v1.CreateUserRequest, *v1.CreateUserResponse] {
+			return Arrange[*v1.CreateUserRequest, *v1.CreateUserResponse]().
+				WithValidate(ForMessage[*v1.CreateUserRequest]().
+					AssertNonZero("user.id", req.GetUser().GetId()))
+		})
+		conn := dialTestServer(t, srv, grpc.UnaryInterceptor(registry.UnaryServerInterceptor()))
+		res := &v1.CreateUserResponse{}
+
+		// act
+		err := conn.Invoke(context.Background(), createUserMethod, &v1.CreateUserRequest{User: &v1.User{Id: "abc123"}}, res)
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, 1, srv.calls)
+		assert.Equal(t, "abc123", res.GetUser().GetId())
+	})
+
+	t.Run("it should serve the request in place of the handler", func(t *testing.T) {
+		// arrange
+		srv := &testUserServer{}
+		registry := NewRegistry(WithServeCode(codes.NotFound))
+		Register(registry, updateUserMethod, Arrange[*v1.UpdateUserRequest, *v1.UpdateUserResponse]().
+			WithServe(func(_ context.Context, req *v1.UpdateUserRequest) (*v1.UpdateUserResponse, error) {
+				if req.GetUser().GetId() != "abc123" {
+					return nil, errors.New("user not found")
+				}
+				return &v1.UpdateUserResponse{User: &v1.User{Id: "abc123", FirstName: "Bob"}}, nil
+			}))
+		conn := dialTestServer(t, srv, grpc.UnaryInterceptor(registry.UnaryServerInterceptor()))
+		res := &v1.UpdateUserResponse{}
+
+		// act
+		okErr := conn.Invoke(context.Background(), updateUserMethod, &v1.UpdateUserRequest{User: &v1.User{Id: "abc123"}}, res)
+		notFoundErr := conn.Invoke(context.Background(), updateUserMethod, &v1.UpdateUserRequest{User: &v1.User{Id: "def456"}}, &v1.UpdateUserResponse{})
+
+		// assert
+		assert.NoError(t, okErr)
+		assert.Equal(t, "Bob", res.GetUser().GetFirstName())
+		assert.Equal(t, codes.NotFound, status.Code(notFoundErr))
+		assert.Equal(t, ServeStage, FromError(notFoundErr).Stage())
+		assert.Equal(t, 0, srv.calls)
+	})
+
+	t.Run("it should pass unregistered methods through", func(t *testing.T) {
+		// arrange
+		srv := &testUserServer{}
+		registry := NewRegistry()
+		Register(registry, createUserMethod, Arrange[*v1.CreateUserRequest, *v1.CreateUserResponse]().
+			WithAuth(func(context.Context, *v1.CreateUserRequest) error {
+				return ErrUnauthenticated
+			}))
+		conn := dialTestServer(t, srv, grpc.UnaryInterceptor(registry.UnaryServerInterceptor()))
+
+		// act
+		err := conn.Invoke(context.Background(), updateUserMethod, &v1.UpdateUserRequest{}, &v1.UpdateUserResponse{})
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, 1, srv.calls)
+	})
+}
+
+// syncUsers sends every request on a new SyncUsers stream and returns the responses
+// received before the stream closed along with the closing error
+func syncUsers(t *testing.T, conn *grpc.ClientConn, reqs ...*v1.CreateUserRequest) ([]*v1.CreateUserResponse, error) {
+	t.Helper()
+	stream, err := conn.NewStream(context.Background(), &userServiceDesc.Streams[0], syncUsersMethod)
+	require.NoError(t, err)
+	for _, req := range reqs {
+		if err := stream.SendMsg(req); err != nil {
+			break
+		}
+	}
+	require.NoError(t, stream.CloseSend())
+	var res []*v1.CreateUserResponse
+	for {
+		msg := &v1.CreateUserResponse{}
+		if err := stream.RecvMsg(msg); err != nil {
+			if errors.Is(err, io.EOF) {
+				return res, nil
+			}
+			return res, err
+		}
+		res = append(res, msg)
+	}
+}
+
+func userIDValidator() MessageValidator[*v1.CreateUserRequest] {
+	return MessageValidatorFunc[*v1.CreateUserRequest](func(ctx context.Context, req *v1.CreateUserRequest) *ValidationErrors {
+		return ForMessage[*v1.CreateUserRequest]().
+			AssertNonZero("user.id", req.GetUser().GetId()).
+			Exec(ctx, req)
+	})
+}
+
+func TestStreamServerInterceptor(t *testing.T) {
+	reqs := []*v1.CreateUserRequest{
+		{User: &v1.User{Id: "abc123"}},
+		{User: &v1.User{FirstName: "bob"}},
+		{User: &v1.User{Id: "def456"}},
+	}
+
+	t.Run("it should skip invalid messages and report them", func(t *testing.T) {
+		// arrange
+		srv := &testUserServer{}
+		var invalid []*Error
+		registry := NewRegistry()
+		RegisterStream(registry, syncUsersMethod, ArrangeStream[*v1.CreateUserRequest, *v1.CreateUserResponse]().
+			WithValidate(userIDValidator()).
+			WithOnInvalid(func(_ context.Context, _ *v1.CreateUserRequest, err *Error) error {
+				invalid = append(invalid, err)
+				return nil
+			}).
+			WithServe(func(_ context.Context, stream Stream[*v1.CreateUserRequest, *v1.CreateUserResponse]) error {
+				for {
+					req, err := stream.Recv()
+					if errors.Is(err, io.EOF) {
+						return nil
+					}
+					if err != nil {
+						return err
+					}
+					if err := stream.Send(&v1.CreateUserResponse{User: req.GetUser()}); err != nil {
+						return err
+					}
+				}
+			}))
+		conn := dialTestServer(t, srv, grpc.StreamInterceptor(registry.StreamServerInterceptor()))
+
+		// act
+		res, err := syncUsers(t, conn, reqs...)
+
+		// assert
+		assert.NoError(t, err)
+		assert.Len(t, res, 2)
+		assert.Equal(t, "abc123", res[0].GetUser().GetId())
+		assert.Equal(t, "def456", res[1].GetUser().GetId())
+		assert.Len(t, invalid, 1)
+		assert.Equal(t, []string{"user.id"}, invalid[0].GetValidationErrors().Paths())
+		assert.Equal(t, 0, srv.calls)
+	})
+
+	t.Run("it should close the stream on the first invalid message when configured", func(t *testing.T) {
+		// arrange
+		srv := &testUserServer{}
+		registry := NewRegistry()
+		RegisterStream(registry, syncUsersMethod, ArrangeStream[*v1.CreateUserRequest, *v1.CreateUserResponse]().
+			WithValidate(userIDValidator()).
+			WithFailOnInvalid())
+		conn := dialTestServer(t, srv, grpc.StreamInterceptor(registry.StreamServerInterceptor()))
+
+		// act
+		res, err := syncUsers(t, conn, reqs...)
+
+		// assert
+		assert.Error(t, err)
+		assert.Len(t, res, 1)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		assert.ErrorIs(t, FromError(err), ErrFieldMustNotBeZeroFailed)
+		assert.Equal(t, 1, srv.calls)
+	})
+
+	t.Run("it should guard the messages received by the handler", func(t *testing.T) {
+		// arrange
+		srv := &testUserServer{}
+		registry := NewRegistry()
+		RegisterStream(registry, syncUsersMethod, ArrangeStream[*v1.CreateUserRequest, *v1.CreateUserResponse]().
+			WithValidate(userIDValidator()))
+		conn := dialTestServer(t, srv, grpc.StreamInterceptor(registry.StreamServerInterceptor()))
+
+		// act
+		res, err := syncUsers(t, conn, reqs...)
+
+		// assert
+		assert.NoError(t, err)
+		assert.Len(t, res, 2)
+		assert.Equal(t, 2, srv.calls)
+	})
+
+	t.Run("it should run open auth before receiving messages", func(t *testing.T) {
+		// arrange
+		srv := &testUserServer{}
+		registry := NewRegistry()
+		RegisterStream(registry, syncUsersMethod, ArrangeStream[*v1.CreateUserRequest, *v1.CreateUserResponse]().
+			WithOpenAuth(func(context.Context) error {
+				return ErrPermissionDenied
+			}).
+			WithValidate(userIDValidator()))
+		conn := dialTestServer(t, srv, grpc.StreamInterceptor(registry.StreamServerInterceptor()))
+
+		// act
+		res, err := syncUsers(t, conn, reqs...)
+
+		// assert
+		assert.Error(t, err)
+		assert.Empty(t, res)
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+		assert.Equal(t, 0, srv.calls)
+	})
+}
@@ -0,0 +1,257 @@
+package resdes
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+)
+
+// ErrIdempotencyKeyReused returned as a custom validation error when a request reuses the idempotency key
+// of a request with a different body
+var ErrIdempotencyKeyReused = errors.New("idempotency key reused with a different request")
+
+// IdempotencyKey a function returning the idempotency key of a request, or "" if it has none
+type IdempotencyKey[T proto.Message] func(context.Context, T) string
+
+// IdempotencyKeyFromField returns the value of the scalar field at the supplied path as the idempotency key.
+// A zero value is no key. Panics if the path does not exist in the message descriptor or is not a scalar field
+func IdempotencyKeyFromField[T proto.Message](path string) IdempotencyKey[T] {
+	var zero T
+	fds, err := resolvePath(zero.ProtoReflect().Descriptor(), path)
+	if err != nil {
+		panic(fmt.Sprintf("resdes: %v", err))
+	}
+	if fd := fds[len(fds)-1]; fd.IsList() || fd.IsMap() || fd.Message() != nil {
+		panic(fmt.Sprintf("resdes: idempotency key %s is not a scalar field", path))
+	}
+	return func(_ context.Context, message T) string {
+		v := valueAt(message.ProtoReflect(), fds)
+		if isZero(v) {
+			return ""
+		}
+		if b, ok := v.([]byte); ok {
+			return string(b)
+		}
+		return fmt.Sprint(v)
+	}
+}
+
+// IdempotencyKeyFromMetadata returns the first value of the supplied key of the incoming gRPC metadata as the idempotency key
+func IdempotencyKeyFromMetadata[T proto.Message](key string) IdempotencyKey[T] {
+	return func(ctx context.Context, _ T) string {
+		if values := metadata.ValueFromIncomingContext(ctx, key); len(values) > 0 {
+			return values[0]
+		}
+		return ""
+	}
+}
+
+// IdempotencyRecord the request hash and Serve result stored for an idempotency key
+type IdempotencyRecord[U any] struct {
+	Hash   []byte
+	Result U
+}
+
+// IdempotencyStore stores the results of Serve by idempotency key
+type IdempotencyStore[U any] interface {
+	// Reserve claims the key for the request with the supplied hash, returning true if it was claimed.
+	// Otherwise it returns the record of the key. If a request with the key and the same hash is being
+	// served, it waits for it to complete, or to be released and claims the key, or for the context to be done
+	Reserve(ctx context.Context, key string, hash []byte) (*IdempotencyRecord[U], bool, error)
+	// Complete stores the result of the request that claimed the key
+	Complete(ctx context.Context, key string, result U) error
+	// Release drops the claim on the key when its request fails, so that a retry can serve it
+	Release(ctx context.Context, key string) error
+}
+
+// idempotency the key and store of an arrangement
+type idempotency[T proto.Message, U any] struct {
+	key   IdempotencyKey[T]
+	store IdempotencyStore[U]
+}
+
+// WithIdempotency runs the Serve stage at most once per idempotency key. A retry with the same key and
+// request body replays the stored result, and one with a different body fails validation with
+// ErrIdempotencyKeyReused. Requests without a key are served as usual
+func (r *Arrangement[T, U]) WithIdempotency(key IdempotencyKey[T], store IdempotencyStore[U]) *Arrangement[T, U] {
+	r.idempotency = &idempotency[T, U]{key: key, store: store}
+	return r
+}
+
+// idempotent runs the Serve stage once per idempotency key of the request, replaying the stored result to retries
+func (r *Arrangement[T, U]) idempotent(ctx context.Context, message T, serve Server[T, U]) (U, *Error) {
+	var key string
+	if r.idempotency != nil {
+		key = r.idempotency.key(ctx, message)
+	}
+	if key == "" {
+		return r.runServe(ctx, message, serve)
+	}
+
+	var zero U
+	hash, err := hashMessage(message)
+	if err != nil {
+		return zero, stageError(ServeStage, err)
+	}
+	store := r.idempotency.store
+	record, reserved, err := store.Reserve(ctx, key, hash)
+	if err != nil {
+		return zero, stageError(ServeStage, err)
+	}
+	if !reserved {
+		if !bytes.Equal(record.Hash, hash) {
+			errs := NewValidationErrors()
+			errs.AddCustomValidationErr(fmt.Errorf("%w: %s", ErrIdempotencyKeyReused, key))
+			serr := &Error{}
+			serr.SetValidationErrors(errs)
+			return zero, serr
+		}
+		return record.Result, nil
+	}
+
+	// release the key if the request fails or panics, so that it can be retried
+	completed := false
+	defer func() {
+		if !completed {
+			_ = store.Release(context.WithoutCancel(ctx), key)
+		}
+	}()
+	res, serr := r.runServe(ctx, message, serve)
+	if serr != nil {
+		return res, serr
+	}
+	// the request has been served, so a failure to store its result is not returned
+	completed = store.Complete(context.WithoutCancel(ctx), key, res) == nil
+	return res, nil
+}
+
+// hashMessage returns the hash of the deterministic encoding of the message
+func hashMessage(m proto.Message) ([]byte, error) {
+	b, err := proto.MarshalOptions{Deterministic: true}.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(b)
+	return sum[:], nil
+}
+
+// MemoryIdempotencyStore an in-memory IdempotencyStore keeping results for a TTL after they are stored.
+// It is safe for concurrent use, and concurrent requests with the same key wait for the first to complete
+type MemoryIdempotencyStore[U any] struct {
+	ttl       time.Duration
+	now       func() time.Time
+	mu        sync.Mutex
+	entries   map[string]*memoryEntry[U]
+	lastSweep time.Time
+}
+
+// memoryEntry a claimed key. done is closed once the key is completed or released
+type memoryEntry[U any] struct {
+	hash      []byte
+	result    U
+	completed bool
+	expires   time.Time
+	done      chan struct{}
+}
+
+// NewMemoryIdempotencyStore creates an in-memory store keeping results for the supplied TTL
+func NewMemoryIdempotencyStore[U any](ttl time.Duration) *MemoryIdempotencyStore[U] {
+	return &MemoryIdempotencyStore[U]{
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[string]*memoryEntry[U]),
+	}
+}
+
+// WithClock sets the clock the TTL of results is measured with
+func (s *MemoryIdempotencyStore[U]) WithClock(now func() time.Time) *MemoryIdempotencyStore[U] {
+	s.now = now
+	return s
+}
+
+func (s *MemoryIdempotencyStore[U]) Reserve(ctx context.Context, key string, hash []byte) (*IdempotencyRecord[U], bool, error) {
+	for {
+		s.mu.Lock()
+		now := s.now()
+		s.sweep(now)
+		e, ok := s.entries[key]
+		if ok && e.completed && !now.Before(e.expires) {
+			delete(s.entries, key)
+			ok = false
+		}
+		if !ok {
+			s.entries[key] = &memoryEntry[U]{hash: hash, done: make(chan struct{})}
+			s.mu.Unlock()
+			return nil, true, nil
+		}
+		if e.completed || !bytes.Equal(e.hash, hash) {
+			record := &IdempotencyRecord[U]{Hash: e.hash, Result: cloneResult(e.result)}
+			s.mu.Unlock()
+			return record, false, nil
+		}
+		s.mu.Unlock()
+
+		// wait for the request being served to complete or be released
+		select {
+		case <-ctx.Done():
+			return nil, false, ctx.Err()
+		case <-e.done:
+		}
+	}
+}
+
+func (s *MemoryIdempotencyStore[U]) Complete(_ context.Context, key string, result U) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.entries[key]
+	if !ok || e.completed {
+		return nil
+	}
+	e.result = cloneResult(result)
+	e.completed = true
+	e.expires = s.now().Add(s.ttl)
+	close(e.done)
+	return nil
+}
+
+func (s *MemoryIdempotencyStore[U]) Release(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.entries[key]
+	if !ok || e.completed {
+		return nil
+	}
+	delete(s.entries, key)
+	close(e.done)
+	return nil
+}
+
+// sweep deletes expired results, at most once per TTL
+func (s *MemoryIdempotencyStore[U]) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < s.ttl {
+		return
+	}
+	s.lastSweep = now
+	for key, e := range s.entries {
+		if e.completed && !now.Before(e.expires) {
+			delete(s.entries, key)
+		}
+	}
+}
+
+// cloneResult clones results that are proto messages, so that stored results are not shared with callers
+func cloneResult[U any](result U) U {
+	if m, ok := any(result).(proto.Message); ok {
+		if c, ok := proto.Clone(m).(U); ok {
+			return c
+		}
+	}
+	return result
+}
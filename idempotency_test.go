@@ -0,0 +1,220 @@
+package resdes
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	v1 "github.com/signal426/resdes/test_protos/gen/test_protos/resdes/v1"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestIdempotency(t *testing.T) {
+	// countingServe returns a new user with the id of the request and the number of calls as its first name
+	countingServe := func(calls *atomic.Int32) Server[*v1.CreateUserRequest, *v1.CreateUserResponse] {
+		return func(ctx context.Context, r *v1.CreateUserRequest) (*v1.CreateUserResponse, error) {
+			n := calls.Add(1)
+			return &v1.CreateUserResponse{User: &v1.User{Id: r.GetUser().GetId(), FirstName: string('0' + rune(n))}}, nil
+		}
+	}
+
+	t.Run("it should replay the result of a retry with the same key", func(t *testing.T) {
+		// arrange
+		var calls atomic.Int32
+		arrangement := Arrange[*v1.CreateUserRequest, *v1.CreateUserResponse]().
+			WithServe(countingServe(&calls)).
+			WithIdempotency(IdempotencyKeyFromField[*v1.CreateUserRequest]("user.id"),
+				NewMemoryIdempotencyStore[*v1.CreateUserResponse](time.Minute))
+		req := &v1.CreateUserRequest{User: &v1.User{Id: "abc123", LastName: "smith"}}
+
+		// act
+		first, ferr := arrangement.Exec(context.Background(), req)
+		first.GetUser().LastName = "changed"
+		second, serr := arrangement.Exec(context.Background(), req)
+
+		// assert
+		assert.Nil(t, ferr)
+		assert.Nil(t, serr)
+		assert.Equal(t, int32(1), calls.Load())
+		assert.Equal(t, "1", second.GetUser().GetFirstName())
+		assert.Empty(t, second.GetUser().GetLastName())
+	})
+
+	t.Run("it should reject a retry with the same key and a different request", func(t *testing.T) {
+		// arrange
+		var calls atomic.Int32
+		arrangement := Arrange[*v1.CreateUserRequest, *v1.CreateUserResponse]().
+			WithServe(countingServe(&calls)).
+			WithIdempotency(IdempotencyKeyFromField[*v1.CreateUserRequest]("user.id"),
+				NewMemoryIdempotencyStore[*v1.CreateUserResponse](time.Minute))
+
+		// act
+		_, ferr := arrangement.Exec(context.Background(), &v1.CreateUserRequest{User: &v1.User{Id: "abc123", LastName: "smith"}})
+		_, serr := arrangement.Exec(context.Background(), &v1.CreateUserRequest{User: &v1.User{Id: "abc123", LastName: "jones"}})
+
+		// assert
+		assert.Nil(t, ferr)
+		assert.Equal(t, ValidateStage, serr.Stage())
+		assert.ErrorIs(t, serr, ErrIdempotencyKeyReused)
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("it should take the key from the incoming metadata", func(t *testing.T) {
+		// arrange
+		var calls atomic.Int32
+		arrangement := Arrange[*v1.CreateUserRequest, *v1.CreateUserResponse]().
+			WithServe(countingServe(&calls)).
+			WithIdempotency(IdempotencyKeyFromMetadata[*v1.CreateUserRequest]("idempotency-key"),
+				NewMemoryIdempotencyStore[*v1.CreateUserResponse](time.Minute))
+		req := &v1.CreateUserRequest{User: &v1.User{Id: "abc123"}}
+		keyed := metadata.NewIncomingContext(context.Background(), metadata.Pairs("idempotency-key", "k1"))
+		other := metadata.NewIncomingContext(context.Background(), metadata.Pairs("idempotency-key", "k2"))
+
+		// act
+		arrangement.Exec(keyed, req)
+		arrangement.Exec(keyed, req)
+		arrangement.Exec(other, req)
+		arrangement.Exec(context.Background(), req)
+		arrangement.Exec(context.Background(), req)
+
+		// assert
+		assert.Equal(t, int32(4), calls.Load())
+	})
+
+	t.Run("it should serve a retry of a request that failed", func(t *testing.T) {
+		// arrange
+		var calls int
+		arrangement := Arrange[*v1.CreateUserRequest, *v1.CreateUserResponse]().
+			WithServe(flakyServe(&calls, 1, errors.New("boom"))).
+			WithIdempotency(IdempotencyKeyFromField[*v1.CreateUserRequest]("user.id"),
+				NewMemoryIdempotencyStore[*v1.CreateUserResponse](time.Minute))
+		req := &v1.CreateUserRequest{User: &v1.User{Id: "abc123"}}
+
+		// act
+		_, ferr := arrangement.Exec(context.Background(), req)
+		res, serr := arrangement.Exec(context.Background(), req)
+
+		// assert
+		assert.NotNil(t, ferr.GetServeError())
+		assert.Nil(t, serr)
+		assert.Equal(t, "abc123", res.GetUser().GetId())
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("it should serve concurrent duplicate requests once", func(t *testing.T) {
+		// arrange
+		var calls atomic.Int32
+		release := make(chan struct{})
+		arrangement := Arrange[*v1.CreateUserRequest, *v1.CreateUserResponse]().
+			WithServe(func(ctx context.Context, r *v1.CreateUserRequest) (*v1.CreateUserResponse, error) {
+				<-release
+				calls.Add(1)
+				return &v1.CreateUserResponse{User: r.GetUser()}, nil
+			}).
+			WithIdempotency(IdempotencyKeyFromField[*v1.CreateUserRequest]("user.id"),
+				NewMemoryIdempotencyStore[*v1.CreateUserResponse](time.Minute))
+		var wg sync.WaitGroup
+		results := make([]*v1.CreateUserResponse, 10)
+		errs := make([]*Error, 10)
+
+		// act
+		for i := range results {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				results[i], errs[i] = arrangement.Exec(context.Background(), &v1.CreateUserRequest{User: &v1.User{Id: "abc123"}})
+			}()
+		}
+		time.Sleep(10 * time.Millisecond)
+		close(release)
+		wg.Wait()
+
+		// assert
+		assert.Equal(t, int32(1), calls.Load())
+		for i := range results {
+			assert.Nil(t, errs[i])
+			assert.Equal(t, "abc123", results[i].GetUser().GetId())
+		}
+	})
+
+	t.Run("it should panic on a key path that is not a scalar field", func(t *testing.T) {
+		assert.Panics(t, func() { IdempotencyKeyFromField[*v1.CreateUserRequest]("user") })
+		assert.Panics(t, func() { IdempotencyKeyFromField[*v1.CreateUserRequest]("user.missing") })
+	})
+}
+
+func TestMemoryIdempotencyStore(t *testing.T) {
+	t.Run("it should expire results after the ttl", func(t *testing.T) {
+		// arrange
+		now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+		store := NewMemoryIdempotencyStore[string](time.Minute).WithClock(func() time.Time { return now })
+		ctx := context.Background()
+		_, reserved, _ := store.Reserve(ctx, "k", []byte("a"))
+		assert.True(t, reserved)
+		assert.NoError(t, store.Complete(ctx, "k", "result"))
+
+		// act
+		now = now.Add(59 * time.Second)
+		record, live, _ := store.Reserve(ctx, "k", []byte("a"))
+		now = now.Add(time.Second)
+		_, expired, _ := store.Reserve(ctx, "k", []byte("a"))
+
+		// assert
+		assert.False(t, live)
+		assert.Equal(t, "result", record.Result)
+		assert.True(t, expired)
+	})
+
+	t.Run("it should return the record of a key being served with a different hash", func(t *testing.T) {
+		// arrange
+		store := NewMemoryIdempotencyStore[string](time.Minute)
+		ctx := context.Background()
+		store.Reserve(ctx, "k", []byte("a"))
+
+		// act
+		record, reserved, err := store.Reserve(ctx, "k", []byte("b"))
+
+		// assert
+		assert.NoError(t, err)
+		assert.False(t, reserved)
+		assert.Equal(t, []byte("a"), record.Hash)
+	})
+
+	t.Run("it should stop waiting for a key being served when the context is done", func(t *testing.T) {
+		// arrange
+		store := NewMemoryIdempotencyStore[string](time.Minute)
+		store.Reserve(context.Background(), "k", []byte("a"))
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		// act
+		_, reserved, err := store.Reserve(ctx, "k", []byte("a"))
+
+		// assert
+		assert.False(t, reserved)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("it should hand a released key to a waiting request", func(t *testing.T) {
+		// arrange
+		store := NewMemoryIdempotencyStore[string](time.Minute)
+		ctx := context.Background()
+		store.Reserve(ctx, "k", []byte("a"))
+		done := make(chan bool)
+		go func() {
+			_, reserved, _ := store.Reserve(ctx, "k", []byte("a"))
+			done <- reserved
+		}()
+
+		// act
+		time.Sleep(10 * time.Millisecond)
+		assert.NoError(t, store.Release(ctx, "k"))
+
+		// assert
+		assert.True(t, <-done)
+	})
+}
@@ -1,14 +1,32 @@
 package resdes
 
 import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
 	"strings"
 	"unicode"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
 )
 
+// NormalizePath converts the field names of a path to their JSON (camelCase) form.
+// Element selectors such as [2] or ["env_name"] are kept as-is
 func NormalizePath(path string) string {
+	if !strings.ContainsRune(path, '_') {
+		return path
+	}
 	sb := strings.Builder{}
+	sb.Grow(len(path))
 	var toUpper bool
+	var sel selectorScanner
 	for _, c := range path {
+		if sel.scan(c) {
+			sb.WriteRune(c)
+			continue
+		}
 		if c == '_' {
 			toUpper = true
 			continue
@@ -27,17 +45,168 @@ func GetPathsFromMask(fieldMask ...string) map[string]struct{} {
 	if fieldMask == nil || len(fieldMask) == 0 {
 		return nil
 	}
-	paths := make(map[string]struct{})
+	paths := make(map[string]struct{}, len(fieldMask))
 	for _, f := range fieldMask {
 		paths[NormalizePath(f)] = struct{}{}
 	}
 	return paths
 }
 
+// MaskWildcard is the field mask path that requests a full replacement of the resource
+const MaskWildcard = "*"
+
+// IsPathInMask reports whether the path is covered by the normalized mask paths (see AIP-134):
+// - the wildcard covers every path
+// - a path covers itself and its subfields
+// - a subfield implies its ancestor messages are touched
 func IsPathInMask(path string, paths map[string]struct{}) bool {
 	if paths == nil {
 		return false
 	}
-	_, inMask := paths[path]
-	return inMask
+	if _, wildcard := paths[MaskWildcard]; wildcard {
+		return true
+	}
+	// field masks cannot address individual elements, so an element
+	// is covered by the mask of its repeated or map field
+	path = stripSelectors(path)
+	if _, inMask := paths[path]; inMask {
+		return true
+	}
+	for i := strings.LastIndexByte(path, '.'); i > 0; i = strings.LastIndexByte(path[:i], '.') {
+		if _, parentInMask := paths[path[:i]]; parentInMask {
+			return true
+		}
+	}
+	for p := range paths {
+		if strings.HasPrefix(p, path+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// ApplyMask copies the fields at the supplied field mask paths from src to dst, following AIP-134:
+// - a path may be nested, e.g. user.primary_address.line1
+// - a field in the mask that is not set in src is cleared in dst
+// - message, repeated and map fields in the mask are replaced, not merged
+// - the wildcard replaces dst with src
+// Fields not in the mask are left untouched. Paths are normalized the same as with GetPathsFromMask.
+// Returns an error, without modifying dst, if the messages are of different types or a path is invalid
+func ApplyMask(dst, src proto.Message, paths ...string) error {
+	dm, sm := dst.ProtoReflect(), src.ProtoReflect()
+	md := dm.Descriptor()
+	if sm.Descriptor().FullName() != md.FullName() {
+		return fmt.Errorf("resdes: cannot apply mask of %s to %s", sm.Descriptor().FullName(), md.FullName())
+	}
+	normalized := GetPathsFromMask(paths...)
+	if _, wildcard := normalized[MaskWildcard]; wildcard {
+		proto.Reset(dst)
+		proto.Merge(dst, src)
+		return nil
+	}
+
+	// resolve every path before modifying dst
+	resolved := make([][]protoreflect.FieldDescriptor, 0, len(normalized))
+	for _, path := range slices.Sorted(maps.Keys(normalized)) {
+		fds, segment, err := resolveMaskPath(md, path)
+		if err != nil {
+			return fmt.Errorf("path: %s, segment: %s: %w", path, segment, err)
+		}
+		resolved = append(resolved, fds)
+	}
+
+	// values are set on dst as-is, so copy them from a clone of src
+	sm = proto.Clone(src).ProtoReflect()
+	for _, fds := range resolved {
+		applyPath(dm, sm, fds)
+	}
+	return nil
+}
+
+func applyPath(dst, src protoreflect.Message, fds []protoreflect.FieldDescriptor) {
+	for _, fd := range fds[:len(fds)-1] {
+		if !src.Has(fd) && !dst.Has(fd) {
+			// nothing to copy, and nothing to clear
+			return
+		}
+		src = src.Get(fd).Message()
+		dst = dst.Mutable(fd).Message()
+	}
+	leaf := fds[len(fds)-1]
+	if src.Has(leaf) {
+		dst.Set(leaf, src.Get(leaf))
+		return
+	}
+	dst.Clear(leaf)
+}
+
+// Project returns a copy of m holding only the fields at the supplied field mask paths (see AIP-157).
+// An empty mask or the wildcard keeps every field
+func Project[M proto.Message](m M, paths ...string) (M, error) {
+	if len(paths) == 0 {
+		return proto.Clone(m).(M), nil
+	}
+	projected := m.ProtoReflect().New().Interface()
+	if err := ApplyMask(projected, m, paths...); err != nil {
+		var zero M
+		return zero, err
+	}
+	return projected.(M), nil
+}
+
+// IndexPath returns the path of the element at index i of the repeated field at path,
+// e.g. user.secondary_addresses[2]
+func IndexPath(path string, i int) string {
+	return path + "[" + strconv.Itoa(i) + "]"
+}
+
+// KeyPath returns the path of the entry at key of the map field at path,
+// e.g. labels["env"]
+func KeyPath(path string, key any) string {
+	if s, ok := key.(string); ok {
+		return path + "[" + strconv.Quote(s) + "]"
+	}
+	return path + "[" + fmt.Sprint(key) + "]"
+}
+
+// stripSelectors removes the element selectors from a path,
+// e.g. user.secondaryAddresses[2].line1 becomes user.secondaryAddresses.line1
+func stripSelectors(path string) string {
+	if !strings.ContainsRune(path, '[') {
+		return path
+	}
+	sb := strings.Builder{}
+	var sel selectorScanner
+	for _, c := range path {
+		if !sel.scan(c) {
+			sb.WriteRune(c)
+		}
+	}
+	return sb.String()
+}
+
+// selectorScanner tracks whether runes of a path are part of an element selector
+type selectorScanner struct {
+	inSelector bool
+	inQuote    bool
+	escaped    bool
+}
+
+// scan reports whether c is part of an element selector, including its brackets
+func (s *selectorScanner) scan(c rune) bool {
+	if !s.inSelector {
+		s.inSelector = c == '['
+		return s.inSelector
+	}
+	switch {
+	case s.escaped:
+		s.escaped = false
+	case s.inQuote && c == '\\':
+		s.escaped = true
+	case c == '"':
+		s.inQuote = !s.inQuote
+	case !s.inQuote && c == ']':
+		s.inSelector = false
+	}
+	return true
 }
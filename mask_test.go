@@ -0,0 +1,297 @@
+package resdes
+
+import (
+	"context"
+	"testing"
+
+	v1 "github.com/signal426/resdes/test_protos/gen/test_protos/resdes/v1"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+func TestIsPathInMask(t *testing.T) {
+	t.Run("it should match paths in the mask", func(t *testing.T) {
+		// arrange
+		paths := GetPathsFromMask("user.first_name", "user.primary_address")
+
+		// assert
+		assert.True(t, IsPathInMask("user.firstName", paths))
+		assert.False(t, IsPathInMask("user.lastName", paths))
+		assert.False(t, IsPathInMask("user.first", paths))
+		assert.False(t, IsPathInMask("user.firstName", nil))
+	})
+
+	t.Run("it should cover subfields of a path in the mask", func(t *testing.T) {
+		// arrange
+		paths := GetPathsFromMask("user.primary_address")
+
+		// assert
+		assert.True(t, IsPathInMask("user.primaryAddress.line1", paths))
+		assert.False(t, IsPathInMask("user.primaryAddresses.line1", paths))
+	})
+
+	t.Run("it should treat ancestors of a path in the mask as touched", func(t *testing.T) {
+		// arrange
+		paths := GetPathsFromMask("user.primary_address.line1")
+
+		// assert
+		assert.True(t, IsPathInMask("user.primaryAddress", paths))
+		assert.True(t, IsPathInMask("user", paths))
+		assert.False(t, IsPathInMask("user.primaryAddress.line2", paths))
+	})
+
+	t.Run("it should cover every path with the wildcard", func(t *testing.T) {
+		// arrange
+		paths := GetPathsFromMask(MaskWildcard)
+
+		// assert
+		assert.True(t, IsPathInMask("user.primaryAddress.line1", paths))
+		assert.True(t, IsPathInMask("user", paths))
+	})
+
+	t.Run("it should run in mask assertions for subfields of a path in the mask", func(t *testing.T) {
+		// arrange
+		req := &v1.UpdateUserRequest{
+			User: &v1.User{
+				PrimaryAddress: &v1.Address{},
+			},
+		}
+
+		// act
+		err := ForMessage[*v1.UpdateUserRequest]("user.primary_address").
+			AssertNonZeroWhenInMask("user.primary_address.line1", req.GetUser().GetPrimaryAddress().GetLine1()).
+			AssertNonZeroWhenInMask("user.first_name", req.GetUser().GetFirstName()).
+			Exec(context.Background(), req)
+
+		// assert
+		assert.Error(t, err)
+		assert.Equal(t, []string{"user.primary_address.line1"}, err.Paths())
+	})
+}
+
+func TestElementPaths(t *testing.T) {
+	t.Run("it should build element paths", func(t *testing.T) {
+		// assert
+		assert.Equal(t, "user.secondary_addresses[2]", IndexPath("user.secondary_addresses", 2))
+		assert.Equal(t, `labels["env"]`, KeyPath("labels", "env"))
+		assert.Equal(t, "counts[7]", KeyPath("counts", int64(7)))
+	})
+
+	t.Run("it should keep element selectors when normalizing", func(t *testing.T) {
+		// assert
+		assert.Equal(t, "user.secondaryAddresses[2].line1", NormalizePath("user.secondary_addresses[2].line1"))
+		assert.Equal(t, `user.labels["env_name"].firstName`, NormalizePath(`user.labels["env_name"].first_name`))
+		assert.Equal(t, `labels["a]_b\"_c"]`, NormalizePath(`labels["a]_b\"_c"]`))
+	})
+
+	t.Run("it should match element paths against their field in the mask", func(t *testing.T) {
+		// arrange
+		paths := GetPathsFromMask("user.secondary_addresses", "labels")
+
+		// assert
+		assert.True(t, IsPathInMask("user.secondaryAddresses[2].line1", paths))
+		assert.True(t, IsPathInMask(`labels["env"]`, paths))
+		assert.False(t, IsPathInMask("user.primaryAddress[0]", paths))
+	})
+}
+
+func TestMaskValidations(t *testing.T) {
+	t.Run("it should report unknown and repeated mask paths on the mask field", func(t *testing.T) {
+		// arrange
+		req := &v1.UpdateUserRequest{
+			User: &v1.User{Id: "abc123"},
+			UpdateMask: &fieldmaskpb.FieldMask{
+				Paths: []string{"user.frist_name", "user.firstName", "user.primary_address.line1", "user.secondary_addresses.line1", "user.labels", "user.id.value"},
+			},
+		}
+
+		// act
+		err := ForMessage[*v1.UpdateUserRequest](req.GetUpdateMask().GetPaths()...).
+			ValidateMask().
+			Exec(context.Background(), req)
+
+		// assert
+		assert.Equal(t, []string{"update_mask"}, err.Paths())
+		fe := err.FieldErrors[0]
+		assert.Equal(t, ValidMaskPaths, fe.Policy)
+		assert.Equal(t, []string{"user.frist_name", "user.secondary_addresses.line1", "user.id.value"}, fe.Value)
+		assert.ErrorIs(t, err, ErrFieldPathNotFound)
+		assert.ErrorIs(t, err, ErrFieldMaskPathThroughRepeated)
+		assert.Contains(t, err.Error(), "path: user.frist_name, segment: frist_name")
+	})
+
+	t.Run("it should accept the wildcard and an empty mask", func(t *testing.T) {
+		for _, mask := range [][]string{{"*"}, nil} {
+			// act
+			err := ForMessage[*v1.UpdateUserRequest](mask...).
+				ValidateMask().
+				Exec(context.Background(), &v1.UpdateUserRequest{})
+
+			// assert
+			assert.Nil(t, err)
+		}
+	})
+
+	t.Run("it should assert the paths of a mask as a policy", func(t *testing.T) {
+		// act
+		err := ForMessage[*v1.User]().
+			AssertValidMask("read_mask", []string{"first_name", "primaryAddress", "nope"}).
+			Exec(context.Background(), &v1.User{})
+
+		// assert
+		assert.Equal(t, []string{"read_mask"}, err.Paths())
+		assert.Equal(t, []string{"nope"}, err.FieldErrors[0].Value)
+	})
+
+	t.Run("it should validate the mask supplied to a schema", func(t *testing.T) {
+		// arrange
+		schema := NewSchema[*v1.UpdateUserRequest]().ValidateMask()
+
+		// act
+		err := schema.Exec(context.Background(), &v1.UpdateUserRequest{}, "user.last_nme")
+
+		// assert
+		assert.ErrorIs(t, err, ErrFieldPathNotFound)
+		assert.Nil(t, schema.Exec(context.Background(), &v1.UpdateUserRequest{}, "user.last_name"))
+	})
+
+	t.Run("it should report on update_mask when a read mask is declared before it", func(t *testing.T) {
+		// arrange
+		req := &v1.PatchUserRequest{
+			ReadMask:   &fieldmaskpb.FieldMask{Paths: []string{"user.id"}},
+			UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"user.nope"}},
+		}
+
+		// act
+		err := ForMessage[*v1.PatchUserRequest](req.GetUpdateMask().GetPaths()...).
+			ValidateMask().
+			Exec(context.Background(), req)
+		schemaErr := NewSchema[*v1.PatchUserRequest]().
+			ValidateMask().
+			Exec(context.Background(), req, req.GetUpdateMask().GetPaths()...)
+
+		// assert
+		assert.Equal(t, []string{"update_mask"}, err.Paths())
+		assert.Equal(t, []string{"update_mask"}, schemaErr.Paths())
+	})
+}
+
+func TestApplyMask(t *testing.T) {
+	stored := func() *v1.User {
+		return &v1.User{
+			Id:                 "abc123",
+			FirstName:          "bob",
+			LastName:           "Bobson",
+			PrimaryAddress:     &v1.Address{Line1: "1 Main St", Line2: "Apt 2"},
+			SecondaryAddresses: []*v1.Address{{Line1: "2 Side St"}, {Line1: "3 Back St"}},
+			Labels:             map[string]string{"env": "prod", "team": "core"},
+			Status:             v1.UserStatus_USER_STATUS_ACTIVE,
+			Contact:            &v1.User_Email{Email: "bob@example.com"},
+		}
+	}
+
+	t.Run("it should copy, clear and replace the masked fields", func(t *testing.T) {
+		// arrange
+		dst := stored()
+		src := &v1.User{
+			Id:                 "ignored",
+			FirstName:          "alice",
+			PrimaryAddress:     &v1.Address{Line1: "9 New St"},
+			SecondaryAddresses: []*v1.Address{{Line1: "4 Other St"}},
+			Labels:             map[string]string{"env": "dev"},
+		}
+		expected := stored()
+		expected.FirstName = "alice"
+		expected.LastName = ""
+		expected.PrimaryAddress.Line1 = "9 New St"
+		expected.SecondaryAddresses = []*v1.Address{{Line1: "4 Other St"}}
+		expected.Labels = map[string]string{"env": "dev"}
+		expected.Status = v1.UserStatus_USER_STATUS_UNSPECIFIED
+
+		// act
+		err := ApplyMask(dst, src, "first_name", "lastName", "primary_address.line1", "secondary_addresses", "labels", "status")
+
+		// assert
+		assert.NoError(t, err)
+		assert.True(t, proto.Equal(expected, dst), "got %v", dst)
+	})
+
+	t.Run("it should replace masked messages and create missing parents", func(t *testing.T) {
+		// arrange
+		dst := &v1.User{Id: "abc123"}
+		src := &v1.User{PrimaryAddress: &v1.Address{Line2: "Apt 3"}}
+		replaced := stored()
+
+		// act
+		err := ApplyMask(dst, src, "primary_address.line2")
+		rerr := ApplyMask(replaced, src, "primary_address", "email")
+
+		// assert
+		assert.NoError(t, err)
+		assert.True(t, proto.Equal(&v1.User{Id: "abc123", PrimaryAddress: &v1.Address{Line2: "Apt 3"}}, dst), "got %v", dst)
+		assert.NoError(t, rerr)
+		assert.Equal(t, "Apt 3", replaced.GetPrimaryAddress().GetLine2())
+		assert.Empty(t, replaced.GetPrimaryAddress().GetLine1())
+		assert.Nil(t, replaced.GetContact())
+	})
+
+	t.Run("it should not alias src", func(t *testing.T) {
+		// arrange
+		dst := &v1.User{}
+		src := &v1.User{PrimaryAddress: &v1.Address{Line1: "1 Main St"}}
+
+		// act
+		err := ApplyMask(dst, src, "primary_address")
+		src.PrimaryAddress.Line1 = "changed"
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, "1 Main St", dst.GetPrimaryAddress().GetLine1())
+	})
+
+	t.Run("it should replace dst with src for the wildcard", func(t *testing.T) {
+		// arrange
+		dst := stored()
+		src := &v1.User{Id: "xyz"}
+
+		// act
+		err := ApplyMask(dst, src, "*")
+
+		// assert
+		assert.NoError(t, err)
+		assert.True(t, proto.Equal(src, dst))
+	})
+
+	t.Run("it should reject invalid paths without modifying dst", func(t *testing.T) {
+		// arrange
+		dst := stored()
+
+		// act
+		err := ApplyMask(dst, &v1.User{}, "first_name", "frist_name")
+		rerr := ApplyMask(dst, &v1.User{}, "secondary_addresses.line1")
+		terr := ApplyMask(dst, &v1.Address{}, "line1")
+
+		// assert
+		assert.ErrorIs(t, err, ErrFieldPathNotFound)
+		assert.ErrorIs(t, rerr, ErrFieldMaskPathThroughRepeated)
+		assert.Error(t, terr)
+		assert.True(t, proto.Equal(stored(), dst))
+	})
+}
+
+func TestProject(t *testing.T) {
+	t.Run("it should return a copy for an empty mask", func(t *testing.T) {
+		// arrange
+		user := &v1.User{Id: "abc123", PrimaryAddress: &v1.Address{Line1: "a"}}
+
+		// act
+		projected, err := Project(user)
+		projected.PrimaryAddress.Line1 = "changed"
+
+		// assert
+		assert.NoError(t, err)
+		assert.NotSame(t, user, projected)
+		assert.Equal(t, "a", user.GetPrimaryAddress().GetLine1())
+	})
+}
@@ -0,0 +1,60 @@
+package resdes
+
+import (
+	"errors"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// defaultMaskField the field mask field errors are reported on
+const defaultMaskField = "update_mask"
+
+// ValidateMask assert that every path of the field mask supplied to ForMessage resolves against the
+// message descriptor, by proto (snake_case) or JSON (camelCase) name, without going through a repeated
+// or map field. Errors are reported on the update_mask field of the message, or its first field mask
+// field other than read_mask if it has none
+func (s *DefaultMessageValidator[T]) ValidateMask() *DefaultMessageValidator[T] {
+	var zero T
+	return s.AssertValidMask(updateMaskFieldName(zero.ProtoReflect().Descriptor()), s.mask)
+}
+
+// AssertValidMask same as ValidateMask, but for the supplied field mask paths, with errors reported on the supplied path
+func (s *DefaultMessageValidator[T]) AssertValidMask(path string, mask []string) *DefaultMessageValidator[T] {
+	var zero T
+	s.rules = append(s.rules, &maskRule{
+		path: path,
+		md:   zero.ProtoReflect().Descriptor(),
+		mask: mask,
+	})
+	return s
+}
+
+// maskRule checks the paths of a field mask against the descriptor of the message they apply to.
+// The field error holds the invalid paths as its value
+type maskRule struct {
+	path string
+	md   protoreflect.MessageDescriptor
+	mask []string
+}
+
+func (r *maskRule) validate(_ protoreflect.Message, errs *ValidationErrors) {
+	var invalid []string
+	var err error
+	for _, p := range r.mask {
+		if p == MaskWildcard {
+			continue
+		}
+		if _, segment, perr := resolveMaskPath(r.md, p); perr != nil {
+			invalid = append(invalid, p)
+			err = errors.Join(err, newFieldMaskPathErr(r.path, p, segment, perr))
+		}
+	}
+	if err != nil {
+		errs.addErr(&FieldError{
+			Path:   r.path,
+			Policy: ValidMaskPaths,
+			Value:  invalid,
+			Err:    err,
+		})
+	}
+}
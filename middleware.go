@@ -0,0 +1,127 @@
+package resdes
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// StageFunc runs a stage of an arrangement, or the whole execution, returning its result and error.
+// Only the Serve and ValidateResponse stages and the whole execution have a result
+type StageFunc[T proto.Message, U any] func(context.Context, T) (U, error)
+
+// Middleware wraps a stage of an arrangement, or the whole execution when the stage is NoStage.
+// Call next to run what is wrapped. The error of the Auth stage is the one returned by Auth, the
+// error of the Validate stage is a *ValidationErrors, and the error of the whole execution is an *Error
+type Middleware[T proto.Message, U any] func(ctx context.Context, stage Stage, message T, next StageFunc[T, U]) (U, error)
+
+// stageMiddleware a middleware and the stages it wraps
+type stageMiddleware[T proto.Message, U any] struct {
+	stages []Stage
+	mw     Middleware[T, U]
+}
+
+// Use adds a middleware wrapping the whole execution. Middleware runs in the order it was added,
+// the first added being the outermost
+func (r *Arrangement[T, U]) Use(mw Middleware[T, U]) *Arrangement[T, U] {
+	r.middleware = append(r.middleware, mw)
+	return r
+}
+
+// UseStage adds a middleware wrapping each of the supplied stages, or every stage if none are supplied.
+// Middleware of a stage runs in the order it was added, the first added being the outermost
+func (r *Arrangement[T, U]) UseStage(mw Middleware[T, U], stages ...Stage) *Arrangement[T, U] {
+	if len(stages) == 0 {
+		stages = arrangementStages
+	}
+	r.stageMiddleware = append(r.stageMiddleware, stageMiddleware[T, U]{stages: stages, mw: mw})
+	return r
+}
+
+// stage wraps the stage func with the middleware registered for the stage and its timeout,
+// recovering any panic
+func (r *Arrangement[T, U]) stage(stage Stage, next StageFunc[T, U]) StageFunc[T, U] {
+	var mws []Middleware[T, U]
+	for _, sm := range r.stageMiddleware {
+		if slices.Contains(sm.stages, stage) {
+			mws = append(mws, sm.mw)
+		}
+	}
+	return recoverStage(stage, timeoutStage(stage, r.timeouts[stage], chain(mws, stage, next)))
+}
+
+// authStage runs the Auth stage as a stage func
+func (r *Arrangement[T, U]) authStage(ctx context.Context, message T) (U, error) {
+	var zero U
+	return zero, r.Auth(ctx, message)
+}
+
+// validateStage runs the Validate stage as a stage func
+func (r *Arrangement[T, U]) validateStage(ctx context.Context, message T) (U, error) {
+	var zero U
+	if errs := r.Validate.Exec(ctx, message); errs != nil {
+		return zero, errs
+	}
+	return zero, nil
+}
+
+// recoverStage wraps next, returning a panic as an *InternalError of the stage
+func recoverStage[T proto.Message, U any](stage Stage, next StageFunc[T, U]) StageFunc[T, U] {
+	return func(ctx context.Context, message T) (res U, err error) {
+		defer func() {
+			if v := recover(); v != nil {
+				err = NewInternalError(stage, v)
+			}
+		}()
+		return next(ctx, message)
+	}
+}
+
+// stageError returns the error of a stage as an *Error. Recovered panics and timeouts are kept
+// as such, and errors returned by Validate or ValidateResponse middleware that are not validation
+// errors are kept as custom validation errors
+func stageError(stage Stage, err error) *Error {
+	serr := &Error{}
+	var (
+		ierr *InternalError
+		terr *TimeoutError
+	)
+	switch {
+	case errors.As(err, &ierr):
+		serr.SetInternalError(ierr)
+	case errors.As(err, &terr):
+		serr.SetTimeoutError(terr)
+	case stage == AuthStage:
+		serr.SetAuthError(err)
+	case stage == ValidateStage:
+		serr.SetValidationErrors(asValidationErrors(err))
+	case stage == ValidateResponseStage:
+		serr.SetResponseValidationError(&ResponseValidationError{Errs: asValidationErrors(err)})
+	default:
+		serr.SetServeError(err)
+	}
+	return serr
+}
+
+// asValidationErrors returns the validation errors of err, or err as a custom validation error
+func asValidationErrors(err error) *ValidationErrors {
+	if errs := ValidationErrorsFromErr(err); errs != nil {
+		return errs
+	}
+	errs := NewValidationErrors()
+	errs.AddCustomValidationErr(err)
+	return errs
+}
+
+// chain wraps next with the middleware, the first being the outermost
+func chain[T proto.Message, U any](mws []Middleware[T, U], stage Stage, next StageFunc[T, U]) StageFunc[T, U] {
+	for i := len(mws) - 1; i >= 0; i-- {
+		mw, inner := mws[i], next
+		next = func(ctx context.Context, message T) (U, error) {
+			return mw(ctx, stage, message, inner)
+		}
+	}
+	return next
+}
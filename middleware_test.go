@@ -0,0 +1,177 @@
+package resdes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	v1 "github.com/signal426/resdes/test_protos/gen/test_protos/resdes/v1"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+)
+
+// recordingMiddleware records the stages it wraps and the errors they returned
+func recordingMiddleware(name string, calls *[]string) Middleware[*v1.CreateUserRequest, *v1.CreateUserResponse] {
+	return func(ctx context.Context, stage Stage, msg *v1.CreateUserRequest, next StageFunc[*v1.CreateUserRequest, *v1.CreateUserResponse]) (*v1.CreateUserResponse, error) {
+		*calls = append(*calls, fmt.Sprintf("%s before %s", name, stage))
+		res, err := next(ctx, msg)
+		*calls = append(*calls, fmt.Sprintf("%s after %s: %v", name, stage, err != nil))
+		return res, err
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	t.Run("it should wrap the execution and each stage in the order added", func(t *testing.T) {
+		// arrange
+		var calls []string
+		req := &v1.CreateUserRequest{User: &v1.User{Id: "abc123"}}
+
+		// act
+		res, err := Arrange[*v1.CreateUserRequest, *v1.CreateUserResponse]().
+			WithAuth(func(ctx context.Context, r *v1.CreateUserRequest) error {
+				calls = append(calls, "auth")
+				return nil
+			}).
+			WithValidate(ForMessage[*v1.CreateUserRequest]().Require("user.id")).
+			WithServe(func(ctx context.Context, r *v1.CreateUserRequest) (*v1.CreateUserResponse, error) {
+				calls = append(calls, "serve")
+				return &v1.CreateUserResponse{User: r.GetUser()}, nil
+			}).
+			Use(recordingMiddleware("exec", &calls)).
+			UseStage(recordingMiddleware("outer", &calls)).
+			UseStage(recordingMiddleware("inner", &calls), AuthStage, ServeStage).
+			Exec(context.Background(), req)
+
+		// assert
+		assert.Nil(t, err)
+		assert.Equal(t, "abc123", res.GetUser().GetId())
+		assert.Equal(t, []string{
+			"exec before none",
+			"outer before auth",
+			"inner before auth",
+			"auth",
+			"inner after auth: false",
+			"outer after auth: false",
+			"outer before validate",
+			"outer after validate: false",
+			"outer before serve",
+			"inner before serve",
+			"serve",
+			"inner after serve: false",
+			"outer after serve: false",
+			"exec after none: false",
+		}, calls)
+	})
+
+	t.Run("it should pass the error of each stage to the middleware", func(t *testing.T) {
+		// arrange
+		var stageErr, execErr error
+
+		// act
+		_, err := Arrange[*v1.CreateUserRequest, *v1.CreateUserResponse]().
+			WithValidate(ForMessage[*v1.CreateUserRequest]().Require("user.id")).
+			UseStage(func(ctx context.Context, stage Stage, msg *v1.CreateUserRequest, next StageFunc[*v1.CreateUserRequest, *v1.CreateUserResponse]) (*v1.CreateUserResponse, error) {
+				res, err := next(ctx, msg)
+				stageErr = err
+				return res, err
+			}, ValidateStage).
+			Use(func(ctx context.Context, stage Stage, msg *v1.CreateUserRequest, next StageFunc[*v1.CreateUserRequest, *v1.CreateUserResponse]) (*v1.CreateUserResponse, error) {
+				res, err := next(ctx, msg)
+				execErr = err
+				return res, err
+			}).
+			Exec(context.Background(), &v1.CreateUserRequest{})
+
+		// assert
+		assert.Equal(t, ValidateStage, err.Stage())
+		var verr *ValidationErrors
+		assert.ErrorAs(t, stageErr, &verr)
+		assert.Equal(t, []string{"user.id"}, verr.Paths())
+		var serr *Error
+		assert.ErrorAs(t, execErr, &serr)
+		assert.Same(t, err, serr)
+	})
+
+	t.Run("it should let middleware retry a stage", func(t *testing.T) {
+		// arrange
+		attempts := 0
+
+		// act
+		res, err := Arrange[*v1.CreateUserRequest, *v1.CreateUserResponse]().
+			WithServe(func(ctx context.Context, r *v1.CreateUserRequest) (*v1.CreateUserResponse, error) {
+				attempts++
+				if attempts < 3 {
+					return nil, errors.New("unavailable")
+				}
+				return &v1.CreateUserResponse{}, nil
+			}).
+			UseStage(func(ctx context.Context, stage Stage, msg *v1.CreateUserRequest, next StageFunc[*v1.CreateUserRequest, *v1.CreateUserResponse]) (*v1.CreateUserResponse, error) {
+				for {
+					res, err := next(ctx, msg)
+					if err == nil || attempts >= 3 {
+						return res, err
+					}
+				}
+			}, ServeStage).
+			Exec(context.Background(), &v1.CreateUserRequest{})
+
+		// assert
+		assert.Nil(t, err)
+		assert.NotNil(t, res)
+		assert.Equal(t, 3, attempts)
+	})
+
+	t.Run("it should keep the stage of errors returned by middleware", func(t *testing.T) {
+		// arrange
+		denied := errors.New("denied")
+		failed := errors.New("failed")
+
+		// act
+		_, authErr := Arrange[*v1.CreateUserRequest, *v1.CreateUserResponse]().
+			WithAuth(func(ctx context.Context, r *v1.CreateUserRequest) error { return nil }).
+			UseStage(func(ctx context.Context, stage Stage, msg *v1.CreateUserRequest, next StageFunc[*v1.CreateUserRequest, *v1.CreateUserResponse]) (*v1.CreateUserResponse, error) {
+				return nil, denied
+			}, AuthStage).
+			Exec(context.Background(), &v1.CreateUserRequest{})
+		_, validateErr := Arrange[*v1.CreateUserRequest, *v1.CreateUserResponse]().
+			WithValidate(ForMessage[*v1.CreateUserRequest]()).
+			UseStage(func(ctx context.Context, stage Stage, msg *v1.CreateUserRequest, next StageFunc[*v1.CreateUserRequest, *v1.CreateUserResponse]) (*v1.CreateUserResponse, error) {
+				return nil, failed
+			}, ValidateStage).
+			Exec(context.Background(), &v1.CreateUserRequest{})
+		_, execErr := Arrange[*v1.CreateUserRequest, *v1.CreateUserResponse]().
+			Use(func(ctx context.Context, stage Stage, msg *v1.CreateUserRequest, next StageFunc[*v1.CreateUserRequest, *v1.CreateUserResponse]) (*v1.CreateUserResponse, error) {
+				return nil, failed
+			}).
+			Exec(context.Background(), &v1.CreateUserRequest{})
+
+		// assert
+		assert.Equal(t, AuthStage, authErr.Stage())
+		assert.ErrorIs(t, authErr, denied)
+		assert.Equal(t, ValidateStage, validateErr.Stage())
+		assert.ErrorIs(t, validateErr.GetValidationErrors().GetCustomValidationErr(), failed)
+		assert.Equal(t, ServeStage, execErr.Stage())
+		assert.ErrorIs(t, execErr, failed)
+	})
+
+	t.Run("it should wrap the handler as the serve stage of the interceptor", func(t *testing.T) {
+		// arrange
+		var calls []string
+		registry := NewRegistry()
+		Register(registry, "/resdes.v1.UserService/CreateUser", Arrange[*v1.CreateUserRequest, *v1.CreateUserResponse]().
+			UseStage(recordingMiddleware("mw", &calls)))
+		handlerErr := errors.New("handler failed")
+		info := &grpc.UnaryServerInfo{FullMethod: "/resdes.v1.UserService/CreateUser"}
+
+		// act
+		_, err := registry.UnaryServerInterceptor()(context.Background(), &v1.CreateUserRequest{}, info, func(ctx context.Context, req any) (any, error) {
+			calls = append(calls, "handler")
+			return nil, handlerErr
+		})
+
+		// assert
+		assert.Same(t, handlerErr, err)
+		assert.Equal(t, []string{"mw before serve", "handler", "mw after serve: true"}, calls)
+	})
+}
@@ -0,0 +1,146 @@
+package resdes
+
+import (
+	"cmp"
+	"errors"
+	"reflect"
+)
+
+// errUnordered returned when comparing NaN, which fails every bound
+var errUnordered = errors.New("unordered numbers")
+
+// Bounds holds the inclusive bounds of a Between policy
+type Bounds struct {
+	Min any
+	Max any
+}
+
+// AssertGreaterThan assert that the supplied number is greater than the bound
+func (s *DefaultMessageValidator[T]) AssertGreaterThan(path string, value any, bound any) *DefaultMessageValidator[T] {
+	s.rules = append(s.rules, NewField(path, value, GreaterThan, Always, bound, s.paths))
+	return s
+}
+
+// AssertGreaterOrEqual assert that the supplied number is greater than or equal to the bound
+func (s *DefaultMessageValidator[T]) AssertGreaterOrEqual(path string, value any, bound any) *DefaultMessageValidator[T] {
+	s.rules = append(s.rules, NewField(path, value, GreaterOrEqual, Always, bound, s.paths))
+	return s
+}
+
+// AssertLessThan assert that the supplied number is less than the bound
+func (s *DefaultMessageValidator[T]) AssertLessThan(path string, value any, bound any) *DefaultMessageValidator[T] {
+	s.rules = append(s.rules, NewField(path, value, LessThan, Always, bound, s.paths))
+	return s
+}
+
+// AssertLessOrEqual assert that the supplied number is less than or equal to the bound
+func (s *DefaultMessageValidator[T]) AssertLessOrEqual(path string, value any, bound any) *DefaultMessageValidator[T] {
+	s.rules = append(s.rules, NewField(path, value, LessOrEqual, Always, bound, s.paths))
+	return s
+}
+
+// AssertBetween assert that the supplied number is between min and max, inclusive
+func (s *DefaultMessageValidator[T]) AssertBetween(path string, value any, min any, max any) *DefaultMessageValidator[T] {
+	s.rules = append(s.rules, NewField(path, value, Between, Always, Bounds{Min: min, Max: max}, s.paths))
+	return s
+}
+
+// AssertGreaterThanWhenInMask same as AssertGreaterThan, but only executes if the supplied path is in the field mask
+func (s *DefaultMessageValidator[T]) AssertGreaterThanWhenInMask(path string, value any, bound any) *DefaultMessageValidator[T] {
+	s.rules = append(s.rules, NewField(path, value, GreaterThan, InMask, bound, s.paths))
+	return s
+}
+
+// AssertGreaterOrEqualWhenInMask same as AssertGreaterOrEqual, but only executes if the supplied path is in the field mask
+func (s *DefaultMessageValidator[T]) AssertGreaterOrEqualWhenInMask(path string, value any, bound any) *DefaultMessageValidator[T] {
+	s.rules = append(s.rules, NewField(path, value, GreaterOrEqual, InMask, bound, s.paths))
+	return s
+}
+
+// AssertLessThanWhenInMask same as AssertLessThan, but only executes if the supplied path is in the field mask
+func (s *DefaultMessageValidator[T]) AssertLessThanWhenInMask(path string, value any, bound any) *DefaultMessageValidator[T] {
+	s.rules = append(s.rules, NewField(path, value, LessThan, InMask, bound, s.paths))
+	return s
+}
+
+// AssertLessOrEqualWhenInMask same as AssertLessOrEqual, but only executes if the supplied path is in the field mask
+func (s *DefaultMessageValidator[T]) AssertLessOrEqualWhenInMask(path string, value any, bound any) *DefaultMessageValidator[T] {
+	s.rules = append(s.rules, NewField(path, value, LessOrEqual, InMask, bound, s.paths))
+	return s
+}
+
+// AssertBetweenWhenInMask same as AssertBetween, but only executes if the supplied path is in the field mask
+func (s *DefaultMessageValidator[T]) AssertBetweenWhenInMask(path string, value any, min any, max any) *DefaultMessageValidator[T] {
+	s.rules = append(s.rules, NewField(path, value, Between, InMask, Bounds{Min: min, Max: max}, s.paths))
+	return s
+}
+
+// checkNumber compares the value to the bounds of the policy. The value and bounds
+// must be of the same numeric type, as returned by the field's generated getter
+func (f Field) checkNumber() error {
+	bounds := []any{f.cmpTo}
+	if b, ok := f.cmpTo.(Bounds); ok {
+		bounds = []any{b.Min, b.Max}
+	}
+	results := make([]int, 0, len(bounds))
+	unordered := false
+	for _, bound := range bounds {
+		c, err := compareNumbers(f.value, bound)
+		if errors.Is(err, errUnordered) {
+			unordered = true
+		} else if err != nil {
+			return newFieldsNotComparableErr(f.path, reflect.TypeOf(f.value), reflect.TypeOf(bound))
+		}
+		results = append(results, c)
+	}
+	switch {
+	case unordered:
+		return newFieldBoundErr(f.path, f.value, f.cmpTo, boundErrs[f.policy])
+	case f.policy == GreaterThan && results[0] <= 0:
+		return newFieldBoundErr(f.path, f.value, f.cmpTo, ErrFieldMustBeGreaterThan)
+	case f.policy == GreaterOrEqual && results[0] < 0:
+		return newFieldBoundErr(f.path, f.value, f.cmpTo, ErrFieldMustBeGreaterOrEqual)
+	case f.policy == LessThan && results[0] >= 0:
+		return newFieldBoundErr(f.path, f.value, f.cmpTo, ErrFieldMustBeLessThan)
+	case f.policy == LessOrEqual && results[0] > 0:
+		return newFieldBoundErr(f.path, f.value, f.cmpTo, ErrFieldMustBeLessOrEqual)
+	case f.policy == Between && (len(results) != 2 || results[0] < 0 || results[1] > 0):
+		return newFieldBoundErr(f.path, f.value, f.cmpTo, ErrFieldOutOfRange)
+	}
+	return nil
+}
+
+var boundErrs = map[Policy]error{
+	GreaterThan:    ErrFieldMustBeGreaterThan,
+	GreaterOrEqual: ErrFieldMustBeGreaterOrEqual,
+	LessThan:       ErrFieldMustBeLessThan,
+	LessOrEqual:    ErrFieldMustBeLessOrEqual,
+	Between:        ErrFieldOutOfRange,
+}
+
+// compareNumbers compares two numbers of the same type. NaN compares as
+// unordered
+func compareNumbers(a any, b any) (int, error) {
+	av, bv := reflect.ValueOf(a), reflect.ValueOf(b)
+	if !av.IsValid() || !bv.IsValid() || av.Type() != bv.Type() {
+		return 0, ErrFieldComparisonFailedNotComparable
+	}
+	switch av.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return cmp.Compare(av.Int(), bv.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return cmp.Compare(av.Uint(), bv.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		x, y := av.Float(), bv.Float()
+		switch {
+		case x < y:
+			return -1, nil
+		case x > y:
+			return 1, nil
+		case x == y:
+			return 0, nil
+		}
+		return 0, errUnordered
+	}
+	return 0, ErrFieldComparisonFailedNotComparable
+}
@@ -0,0 +1,138 @@
+package resdes
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	v1 "github.com/signal426/resdes/test_protos/gen/test_protos/resdes/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNumberValidations(t *testing.T) {
+	t.Run("it should compare every numeric kind to its bound", func(t *testing.T) {
+		tests := []struct {
+			name   string
+			assert func(*DefaultMessageValidator[*v1.User]) *DefaultMessageValidator[*v1.User]
+			err    error
+		}{
+			{
+				name: "int32",
+				assert: func(v *DefaultMessageValidator[*v1.User]) *DefaultMessageValidator[*v1.User] {
+					return v.AssertGreaterThan("n", int32(1), int32(1))
+				},
+				err: ErrFieldMustBeGreaterThan,
+			},
+			{
+				name: "int64",
+				assert: func(v *DefaultMessageValidator[*v1.User]) *DefaultMessageValidator[*v1.User] {
+					return v.AssertGreaterOrEqual("n", int64(-2), int64(-1))
+				},
+				err: ErrFieldMustBeGreaterOrEqual,
+			},
+			{
+				name: "uint32",
+				assert: func(v *DefaultMessageValidator[*v1.User]) *DefaultMessageValidator[*v1.User] {
+					return v.AssertLessThan("n", uint32(5), uint32(5))
+				},
+				err: ErrFieldMustBeLessThan,
+			},
+			{
+				name: "uint64",
+				assert: func(v *DefaultMessageValidator[*v1.User]) *DefaultMessageValidator[*v1.User] {
+					return v.AssertLessOrEqual("n", uint64(math.MaxUint64), uint64(math.MaxUint64-1))
+				},
+				err: ErrFieldMustBeLessOrEqual,
+			},
+			{
+				name: "float32",
+				assert: func(v *DefaultMessageValidator[*v1.User]) *DefaultMessageValidator[*v1.User] {
+					return v.AssertBetween("n", float32(1.5), float32(2), float32(3))
+				},
+				err: ErrFieldOutOfRange,
+			},
+			{
+				name: "float64",
+				assert: func(v *DefaultMessageValidator[*v1.User]) *DefaultMessageValidator[*v1.User] {
+					return v.AssertGreaterThan("n", math.NaN(), 0.0)
+				},
+				err: ErrFieldMustBeGreaterThan,
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// act
+				err := tt.assert(ForMessage[*v1.User]()).Exec(context.Background(), &v1.User{})
+
+				// assert
+				assert.ErrorIs(t, err, tt.err)
+			})
+		}
+	})
+
+	t.Run("it should pass values within the bounds", func(t *testing.T) {
+		// act
+		err := ForMessage[*v1.User]().
+			AssertGreaterThan("a", int32(2), int32(1)).
+			AssertGreaterOrEqual("b", int64(1), int64(1)).
+			AssertLessThan("c", uint32(4), uint32(5)).
+			AssertLessOrEqual("d", uint64(5), uint64(5)).
+			AssertBetween("e", float32(2), float32(2), float32(3)).
+			AssertBetween("f", 3.0, 2.0, 3.0).
+			Exec(context.Background(), &v1.User{})
+
+		// assert
+		assert.Nil(t, err)
+	})
+
+	t.Run("it should hold the bound in the field error", func(t *testing.T) {
+		// act
+		err := ForMessage[*v1.User]().
+			AssertGreaterThan("a", int32(1), int32(1)).
+			AssertBetween("b", int64(10), int64(2), int64(3)).
+			Exec(context.Background(), &v1.User{})
+
+		// assert
+		var verr *ValidationErrors
+		assert.ErrorAs(t, err, &verr)
+		assert.Equal(t, []string{"a", "b"}, verr.Paths())
+		assert.Equal(t, GreaterThan, verr.FieldErrors[0].Policy)
+		assert.Equal(t, int32(1), verr.FieldErrors[0].Expected)
+		assert.Equal(t, Between, verr.FieldErrors[1].Policy)
+		assert.Equal(t, Bounds{Min: int64(2), Max: int64(3)}, verr.FieldErrors[1].Expected)
+	})
+
+	t.Run("it should not compare different numeric types", func(t *testing.T) {
+		// act
+		err := ForMessage[*v1.User]().
+			AssertGreaterThan("a", int32(2), int64(1)).
+			AssertBetween("b", 2.0, 1, 3).
+			Exec(context.Background(), &v1.User{})
+
+		// assert
+		assert.ErrorIs(t, err, ErrFieldComparisonFailedNotComparable)
+	})
+
+	t.Run("it should not compare to a nil bound", func(t *testing.T) {
+		// act
+		err := ForMessage[*v1.User]().
+			AssertGreaterThan("n", 1, nil).
+			Exec(context.Background(), &v1.User{})
+
+		// assert
+		assert.ErrorIs(t, err, ErrFieldComparisonFailedNotComparable)
+	})
+
+	t.Run("it should only compare in-mask fields", func(t *testing.T) {
+		// act
+		err := ForMessage[*v1.User]("a").
+			AssertGreaterThanWhenInMask("a", int32(0), int32(1)).
+			AssertLessThanWhenInMask("b", int32(5), int32(1)).
+			Exec(context.Background(), &v1.User{})
+
+		// assert
+		var verr *ValidationErrors
+		assert.ErrorAs(t, err, &verr)
+		assert.Equal(t, []string{"a"}, verr.Paths())
+	})
+}
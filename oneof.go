@@ -0,0 +1,137 @@
+package resdes
+
+import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// RequireOneof assert that a member of the oneof at the supplied path is set. The last segment
+// of the path is the oneof name, e.g. user.contact. Panics if the path does not exist in the message descriptor
+func (s *DefaultMessageValidator[T]) RequireOneof(path string) *DefaultMessageValidator[T] {
+	return s.oneof(path, OneofRequired, Always)
+}
+
+// RequireExactlyOne assert that exactly one member of the oneof at the supplied path is set
+func (s *DefaultMessageValidator[T]) RequireExactlyOne(path string) *DefaultMessageValidator[T] {
+	return s.oneof(path, OneofExactlyOne, Always)
+}
+
+// RequireAtMostOne assert that no more than one member of the oneof at the supplied path is set
+func (s *DefaultMessageValidator[T]) RequireAtMostOne(path string) *DefaultMessageValidator[T] {
+	return s.oneof(path, OneofAtMostOne, Always)
+}
+
+// RequireOneofWhenInMask same as RequireOneof, but only executes if the oneof or one of its members is in the field mask
+func (s *DefaultMessageValidator[T]) RequireOneofWhenInMask(path string) *DefaultMessageValidator[T] {
+	return s.oneof(path, OneofRequired, InMask)
+}
+
+// RequireExactlyOneWhenInMask same as RequireExactlyOne, but only executes if the oneof or one of its members is in the field mask
+func (s *DefaultMessageValidator[T]) RequireExactlyOneWhenInMask(path string) *DefaultMessageValidator[T] {
+	return s.oneof(path, OneofExactlyOne, InMask)
+}
+
+// RequireAtMostOneWhenInMask same as RequireAtMostOne, but only executes if the oneof or one of its members is in the field mask
+func (s *DefaultMessageValidator[T]) RequireAtMostOneWhenInMask(path string) *DefaultMessageValidator[T] {
+	return s.oneof(path, OneofAtMostOne, InMask)
+}
+
+func (s *DefaultMessageValidator[T]) oneof(path string, policy Policy, condition Condition) *DefaultMessageValidator[T] {
+	var zero T
+	fds, od, err := resolveOneof(zero.ProtoReflect().Descriptor(), path)
+	if err != nil {
+		panic(fmt.Sprintf("resdes: %v", err))
+	}
+	s.rules = append(s.rules, &oneofRule{
+		path:      path,
+		fds:       fds,
+		oneof:     od,
+		policy:    policy,
+		condition: condition,
+		paths:     s.paths,
+	})
+	return s
+}
+
+// resolveOneof resolves a dotted path whose last segment is the name of a oneof.
+// The segments before it are resolved as a field path to the message holding the oneof
+func resolveOneof(md protoreflect.MessageDescriptor, path string) ([]protoreflect.FieldDescriptor, protoreflect.OneofDescriptor, error) {
+	var fds []protoreflect.FieldDescriptor
+	name := path
+	if i := strings.LastIndex(path, "."); i >= 0 {
+		var err error
+		if fds, err = resolvePath(md, path[:i]); err != nil {
+			return nil, nil, err
+		}
+		name = path[i+1:]
+		if md = fds[len(fds)-1].Message(); md == nil || fds[len(fds)-1].IsList() || fds[len(fds)-1].IsMap() {
+			return nil, nil, newFieldPathNotFoundErr(path, name)
+		}
+	}
+	od := md.Oneofs().ByName(protoreflect.Name(name))
+	if od == nil {
+		return nil, nil, newFieldPathNotFoundErr(path, name)
+	}
+	return fds, od, nil
+}
+
+// oneofRule checks how many members of a oneof are set. The field error is reported
+// at the oneof path, with the names of the members that were set as its value
+type oneofRule struct {
+	path      string
+	fds       []protoreflect.FieldDescriptor
+	oneof     protoreflect.OneofDescriptor
+	policy    Policy
+	condition Condition
+	paths     map[string]struct{}
+}
+
+func (r *oneofRule) validate(m protoreflect.Message, errs *ValidationErrors) {
+	for _, fd := range r.fds {
+		m = m.Get(fd).Message()
+	}
+	members := r.oneof.Fields()
+	names := make([]string, 0, members.Len())
+	set := make([]string, 0, 1)
+	for i := 0; i < members.Len(); i++ {
+		fd := members.Get(i)
+		names = append(names, string(fd.Name()))
+		if m.Has(fd) {
+			set = append(set, string(fd.Name()))
+		}
+	}
+	field := NewField(r.path, set, r.policy, r.condition, names, r.paths)
+	field.inMask = field.inMask || r.memberInMask(names)
+	if err := field.Validate(); err != nil {
+		errs.addFieldErr(field, err)
+	}
+}
+
+// memberInMask reports whether a member of the oneof is in the field mask.
+// Update masks name the members of a oneof rather than the oneof itself
+func (r *oneofRule) memberInMask(names []string) bool {
+	prefix := ""
+	if i := strings.LastIndex(r.path, "."); i >= 0 {
+		prefix = r.path[:i+1]
+	}
+	for _, name := range names {
+		if IsPathInMask(NormalizePath(prefix+name), r.paths) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkOneof checks the number of oneof members that were set
+func (f Field) checkOneof() error {
+	set, _ := f.value.([]string)
+	switch {
+	case len(set) == 0 && (f.policy == OneofRequired || f.policy == OneofExactlyOne):
+		return newFieldOneofErr(f.path, set, ErrFieldOneofNotSet)
+	case len(set) > 1 && (f.policy == OneofExactlyOne || f.policy == OneofAtMostOne):
+		return newFieldOneofErr(f.path, set, ErrFieldOneofMultipleSet)
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package resdes
+
+import (
+	"context"
+	"testing"
+
+	v1 "github.com/signal426/resdes/test_protos/gen/test_protos/resdes/v1"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+func TestOneofValidations(t *testing.T) {
+	t.Run("it should report an unset oneof on the oneof path", func(t *testing.T) {
+		// arrange
+		req := &v1.CreateUserRequest{User: &v1.User{}}
+		expected := &ValidationErrors{
+			FieldErrors: []*FieldError{
+				{
+					Path:   "user.contact",
+					Policy: OneofRequired,
+					Err:    newFieldOneofErr("user.contact", []string{}, ErrFieldOneofNotSet),
+				},
+			},
+		}
+
+		// act
+		err := ForMessage[*v1.CreateUserRequest]().
+			RequireOneof("user.contact").
+			RequireAtMostOne("user.contact").
+			Exec(context.Background(), req)
+
+		// assert
+		assert.ErrorIs(t, err, ErrFieldOneofNotSet)
+		assert.Equal(t, []string{"user.contact"}, err.Paths())
+		assert.Equal(t, []string{"email", "phone"}, err.FieldErrors[0].Expected)
+		assert.Equal(t, expected.Error(), err.Error())
+	})
+
+	t.Run("it should pass when a member is set", func(t *testing.T) {
+		// arrange
+		user := &v1.User{Contact: &v1.User_Phone{Phone: "555-0100"}}
+
+		// act
+		err := ForMessage[*v1.User]().
+			RequireOneof("contact").
+			RequireExactlyOne("contact").
+			RequireAtMostOne("contact").
+			Exec(context.Background(), user)
+
+		// assert
+		assert.Nil(t, err)
+	})
+
+	t.Run("it should list the members that were set", func(t *testing.T) {
+		// act
+		field := NewField("contact", []string{"email", "phone"}, OneofExactlyOne, Always, []string{"email", "phone"}, nil)
+		err := field.Validate()
+
+		// assert
+		assert.ErrorIs(t, err, ErrFieldOneofMultipleSet)
+		assert.Equal(t, []string{"email", "phone"}, FieldErrorFromField(field, err).Value)
+	})
+
+	t.Run("it should only check the oneof when a member is in the mask", func(t *testing.T) {
+		tests := []struct {
+			name  string
+			paths []string
+			err   error
+		}{
+			{name: "member in mask", paths: []string{"user.email"}, err: ErrFieldOneofNotSet},
+			{name: "oneof in mask", paths: []string{"user.contact"}, err: ErrFieldOneofNotSet},
+			{name: "parent in mask", paths: []string{"user"}, err: ErrFieldOneofNotSet},
+			{name: "not in mask", paths: []string{"user.first_name"}},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// arrange
+				req := &v1.UpdateUserRequest{
+					User:       &v1.User{},
+					UpdateMask: &fieldmaskpb.FieldMask{Paths: tt.paths},
+				}
+
+				// act
+				err := ForMessage[*v1.UpdateUserRequest](req.GetUpdateMask().GetPaths()...).
+					RequireOneofWhenInMask("user.contact").
+					Exec(context.Background(), req)
+
+				// assert
+				if tt.err == nil {
+					assert.Nil(t, err)
+					return
+				}
+				assert.ErrorIs(t, err, tt.err)
+			})
+		}
+	})
+
+	t.Run("it should panic on an unknown oneof", func(t *testing.T) {
+		assert.Panics(t, func() { ForMessage[*v1.User]().RequireOneof("first_name") })
+		assert.Panics(t, func() { ForMessage[*v1.CreateUserRequest]().RequireOneof("user.nope") })
+		assert.Panics(t, func() { ForMessage[*v1.User]().RequireOneof("secondary_addresses.contact") })
+	})
+}
@@ -7,8 +7,51 @@ const (
 	NotEqualTo
 	MustEqual
 	Custom
+	MinLength
+	MaxLength
+	Pattern
+	Prefix
+	Suffix
+	Contains
+	Email
+	Hostname
+	IP
+	URI
+	UUID
+	GreaterThan
+	GreaterOrEqual
+	LessThan
+	LessOrEqual
+	Between
+	EnumDefined
+	EnumSpecified
+	EnumIn
+	OneofRequired
+	OneofExactlyOne
+	OneofAtMostOne
+	Forbidden
+	TimestampValid
+	TimestampPast
+	TimestampFuture
+	TimestampWithin
+	TimestampBefore
+	TimestampAfter
+	DurationMin
+	DurationMax
+	ValidMaskPaths
 )
 
+// policies is every built-in policy, used to decode policies from their reason
+var policies = []Policy{
+	NonZero, NotEqualTo, MustEqual, Custom, MinLength, MaxLength,
+	Pattern, Prefix, Suffix, Contains, Email, Hostname, IP, URI, UUID,
+	GreaterThan, GreaterOrEqual, LessThan, LessOrEqual, Between,
+	EnumDefined, EnumSpecified, EnumIn,
+	OneofRequired, OneofExactlyOne, OneofAtMostOne, Forbidden,
+	TimestampValid, TimestampPast, TimestampFuture, TimestampWithin, TimestampBefore, TimestampAfter,
+	DurationMin, DurationMax, ValidMaskPaths,
+}
+
 func (p Policy) String() string {
 	switch p {
 	case NonZero:
@@ -19,14 +62,234 @@ func (p Policy) String() string {
 		return "must not equal"
 	case Custom:
 		return "custom evaluation"
+	case MinLength:
+		return "min length"
+	case MaxLength:
+		return "max length"
+	case Pattern:
+		return "pattern"
+	case Prefix:
+		return "prefix"
+	case Suffix:
+		return "suffix"
+	case Contains:
+		return "contains"
+	case Email:
+		return "email"
+	case Hostname:
+		return "hostname"
+	case IP:
+		return "ip address"
+	case URI:
+		return "uri"
+	case UUID:
+		return "uuid"
+	case GreaterThan:
+		return "greater than"
+	case GreaterOrEqual:
+		return "greater or equal"
+	case LessThan:
+		return "less than"
+	case LessOrEqual:
+		return "less or equal"
+	case Between:
+		return "between"
+	case EnumDefined:
+		return "enum defined"
+	case EnumSpecified:
+		return "enum specified"
+	case EnumIn:
+		return "enum in"
+	case OneofRequired:
+		return "oneof required"
+	case OneofExactlyOne:
+		return "oneof exactly one"
+	case OneofAtMostOne:
+		return "oneof at most one"
+	case Forbidden:
+		return "forbidden"
+	case TimestampValid:
+		return "valid timestamp"
+	case TimestampPast:
+		return "timestamp in past"
+	case TimestampFuture:
+		return "timestamp in future"
+	case TimestampWithin:
+		return "timestamp within window"
+	case TimestampBefore:
+		return "timestamp before"
+	case TimestampAfter:
+		return "timestamp after"
+	case DurationMin:
+		return "min duration"
+	case DurationMax:
+		return "max duration"
+	case ValidMaskPaths:
+		return "valid mask paths"
 	default:
 		return "unknown policy"
 	}
 }
 
+// Reason returns a machine-readable identifier for the policy, suitable for
+// use as the reason on a google.rpc.BadRequest field violation
+func (p Policy) Reason() string {
+	switch p {
+	case NonZero:
+		return "NON_ZERO"
+	case MustEqual:
+		return "MUST_EQUAL"
+	case NotEqualTo:
+		return "MUST_NOT_EQUAL"
+	case Custom:
+		return "CUSTOM"
+	case MinLength:
+		return "MIN_LENGTH"
+	case MaxLength:
+		return "MAX_LENGTH"
+	case Pattern:
+		return "PATTERN"
+	case Prefix:
+		return "PREFIX"
+	case Suffix:
+		return "SUFFIX"
+	case Contains:
+		return "CONTAINS"
+	case Email:
+		return "EMAIL"
+	case Hostname:
+		return "HOSTNAME"
+	case IP:
+		return "IP"
+	case URI:
+		return "URI"
+	case UUID:
+		return "UUID"
+	case GreaterThan:
+		return "GREATER_THAN"
+	case GreaterOrEqual:
+		return "GREATER_OR_EQUAL"
+	case LessThan:
+		return "LESS_THAN"
+	case LessOrEqual:
+		return "LESS_OR_EQUAL"
+	case Between:
+		return "BETWEEN"
+	case EnumDefined:
+		return "ENUM_DEFINED"
+	case EnumSpecified:
+		return "ENUM_SPECIFIED"
+	case EnumIn:
+		return "ENUM_IN"
+	case OneofRequired:
+		return "ONEOF_REQUIRED"
+	case OneofExactlyOne:
+		return "ONEOF_EXACTLY_ONE"
+	case OneofAtMostOne:
+		return "ONEOF_AT_MOST_ONE"
+	case Forbidden:
+		return "FORBIDDEN"
+	case TimestampValid:
+		return "TIMESTAMP_VALID"
+	case TimestampPast:
+		return "TIMESTAMP_PAST"
+	case TimestampFuture:
+		return "TIMESTAMP_FUTURE"
+	case TimestampWithin:
+		return "TIMESTAMP_WITHIN"
+	case TimestampBefore:
+		return "TIMESTAMP_BEFORE"
+	case TimestampAfter:
+		return "TIMESTAMP_AFTER"
+	case DurationMin:
+		return "DURATION_MIN"
+	case DurationMax:
+		return "DURATION_MAX"
+	case ValidMaskPaths:
+		return "VALID_MASK_PATHS"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+func policyFromReason(reason string) Policy {
+	for _, p := range policies {
+		if p.Reason() == reason {
+			return p
+		}
+	}
+	return Custom
+}
+
 type Condition uint32
 
 const (
 	Always Condition = iota
 	InMask
+	// When runs the rule only if its predicate holds for the message
+	When
 )
+
+// Stage identifies a step in the execution of an Arrangement
+type Stage uint32
+
+const (
+	NoStage Stage = iota
+	AuthStage
+	ValidateStage
+	ServeStage
+	ValidateResponseStage
+)
+
+// arrangementStages the stages of an Arrangement, in the order they run
+var arrangementStages = []Stage{AuthStage, ValidateStage, ServeStage, ValidateResponseStage}
+
+func (s Stage) String() string {
+	switch s {
+	case AuthStage:
+		return "auth"
+	case ValidateStage:
+		return "validate"
+	case ServeStage:
+		return "serve"
+	case ValidateResponseStage:
+		return "validate_response"
+	default:
+		return "none"
+	}
+}
+
+// Reason returns a machine-readable identifier for a failure in the stage,
+// suitable for use as the reason on a google.rpc.ErrorInfo
+func (s Stage) Reason() string {
+	switch s {
+	case AuthStage:
+		return "AUTH_FAILED"
+	case ValidateStage:
+		return "VALIDATION_FAILED"
+	case ServeStage:
+		return "SERVE_FAILED"
+	case ValidateResponseStage:
+		return "RESPONSE_VALIDATION_FAILED"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+func stageFromString(name string) Stage {
+	for _, s := range arrangementStages {
+		if s.String() == name {
+			return s
+		}
+	}
+	return NoStage
+}
+
+func stageFromReason(reason string) Stage {
+	for _, s := range arrangementStages {
+		if s.Reason() == reason {
+			return s
+		}
+	}
+	return NoStage
+}
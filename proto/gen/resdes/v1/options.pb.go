@@ -0,0 +1,212 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v5.29.3
+// source: resdes/v1/options.proto
+
+package resdesv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	descriptorpb "google.golang.org/protobuf/types/descriptorpb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// FieldRules declares the assertions to run against a field. Rules declared
+// on the fields of a nested message are applied at the nested field path
+// when the nested message is set
+type FieldRules struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// the field must not be set to its zero value
+	Required bool `protobuf:"varint,1,opt,name=required,proto3" json:"required,omitempty"`
+	// only run the rules if the field path is in the field mask of the request
+	InMaskOnly bool `protobuf:"varint,2,opt,name=in_mask_only,json=inMaskOnly,proto3" json:"in_mask_only,omitempty"`
+	// the field must not equal the value. Written as a literal of the field's
+	// kind: the text of a string, a number, true/false or an enum value name
+	NotEqual *string `protobuf:"bytes,3,opt,name=not_equal,json=notEqual,proto3,oneof" json:"not_equal,omitempty"`
+	// the field must equal the value. Written the same way as not_equal
+	Equal *string `protobuf:"bytes,4,opt,name=equal,proto3,oneof" json:"equal,omitempty"`
+	// the minimum length of a string field in runes
+	MinLen *uint32 `protobuf:"varint,5,opt,name=min_len,json=minLen,proto3,oneof" json:"min_len,omitempty"`
+	// the maximum length of a string field in runes
+	MaxLen        *uint32 `protobuf:"varint,6,opt,name=max_len,json=maxLen,proto3,oneof" json:"max_len,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FieldRules) Reset() {
+	*x = FieldRules{}
+	mi := &file_resdes_v1_options_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FieldRules) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FieldRules) ProtoMessage() {}
+
+func (x *FieldRules) ProtoReflect() protoreflect.Message {
+	mi := &file_resdes_v1_options_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FieldRules.ProtoReflect.Descriptor instead.
+func (*FieldRules) Descriptor() ([]byte, []int) {
+	return file_resdes_v1_options_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *FieldRules) GetRequired() bool {
+	if x != nil {
+		return x.Required
+	}
+	return false
+}
+
+func (x *FieldRules) GetInMaskOnly() bool {
+	if x != nil {
+		return x.InMaskOnly
+	}
+	return false
+}
+
+func (x *FieldRules) GetNotEqual() string {
+	if x != nil && x.NotEqual != nil {
+		return *x.NotEqual
+	}
+	return ""
+}
+
+func (x *FieldRules) GetEqual() string {
+	if x != nil && x.Equal != nil {
+		return *x.Equal
+	}
+	return ""
+}
+
+func (x *FieldRules) GetMinLen() uint32 {
+	if x != nil && x.MinLen != nil {
+		return *x.MinLen
+	}
+	return 0
+}
+
+func (x *FieldRules) GetMaxLen() uint32 {
+	if x != nil && x.MaxLen != nil {
+		return *x.MaxLen
+	}
+	return 0
+}
+
+var file_resdes_v1_options_proto_extTypes = []protoimpl.ExtensionInfo{
+	{
+		ExtendedType:  (*descriptorpb.FieldOptions)(nil),
+		ExtensionType: (*FieldRules)(nil),
+		Field:         50426,
+		Name:          "resdes.v1.field",
+		Tag:           "bytes,50426,opt,name=field",
+		Filename:      "resdes/v1/options.proto",
+	},
+}
+
+// Extension fields to descriptorpb.FieldOptions.
+var (
+	// rules protoc-gen-resdes generates assertions for
+	//
+	// optional resdes.v1.FieldRules field = 50426;
+	E_Field = &file_resdes_v1_options_proto_extTypes[0]
+)
+
+var File_resdes_v1_options_proto protoreflect.FileDescriptor
+
+const file_resdes_v1_options_proto_rawDesc = "" +
+	"\n" +
+	"\x17resdes/v1/options.proto\x12\tresdes.v1\x1a google/protobuf/descriptor.proto\"\xf3\x01\n" +
+	"\n" +
+	"FieldRules\x12\x1a\n" +
+	"\brequired\x18\x01 \x01(\bR\brequired\x12 \n" +
+	"\fin_mask_only\x18\x02 \x01(\bR\n" +
+	"inMaskOnly\x12 \n" +
+	"\tnot_equal\x18\x03 \x01(\tH\x00R\bnotEqual\x88\x01\x01\x12\x19\n" +
+	"\x05equal\x18\x04 \x01(\tH\x01R\x05equal\x88\x01\x01\x12\x1c\n" +
+	"\amin_len\x18\x05 \x01(\rH\x02R\x06minLen\x88\x01\x01\x12\x1c\n" +
+	"\amax_len\x18\x06 \x01(\rH\x03R\x06maxLen\x88\x01\x01B\f\n" +
+	"\n" +
+	"_not_equalB\b\n" +
+	"\x06_equalB\n" +
+	"\n" +
+	"\b_min_lenB\n" +
+	"\n" +
+	"\b_max_len:L\n" +
+	"\x05field\x12\x1d.google.protobuf.FieldOptions\x18\xfa\x89\x03 \x01(\v2\x15.resdes.v1.FieldRulesR\x05fieldB:Z8github.com/signal426/resdes/proto/gen/resdes/v1;resdesv1b\x06proto3"
+
+var (
+	file_resdes_v1_options_proto_rawDescOnce sync.Once
+	file_resdes_v1_options_proto_rawDescData []byte
+)
+
+func file_resdes_v1_options_proto_rawDescGZIP() []byte {
+	file_resdes_v1_options_proto_rawDescOnce.Do(func() {
+		file_resdes_v1_options_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_resdes_v1_options_proto_rawDesc), len(file_resdes_v1_options_proto_rawDesc)))
+	})
+	return file_resdes_v1_options_proto_rawDescData
+}
+
+var file_resdes_v1_options_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_resdes_v1_options_proto_goTypes = []any{
+	(*FieldRules)(nil),                // 0: resdes.v1.FieldRules
+	(*descriptorpb.FieldOptions)(nil), // 1: google.protobuf.FieldOptions
+}
+var file_resdes_v1_options_proto_depIdxs = []int32{
+	1, // 0: resdes.v1.field:extendee -> google.protobuf.FieldOptions
+	0, // 1: resdes.v1.field:type_name -> resdes.v1.FieldRules
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	1, // [1:2] is the sub-list for extension type_name
+	0, // [0:1] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_resdes_v1_options_proto_init() }
+func file_resdes_v1_options_proto_init() {
+	if File_resdes_v1_options_proto != nil {
+		return
+	}
+	file_resdes_v1_options_proto_msgTypes[0].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_resdes_v1_options_proto_rawDesc), len(file_resdes_v1_options_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 1,
+			NumServices:   0,
+		},
+		GoTypes:           file_resdes_v1_options_proto_goTypes,
+		DependencyIndexes: file_resdes_v1_options_proto_depIdxs,
+		MessageInfos:      file_resdes_v1_options_proto_msgTypes,
+		ExtensionInfos:    file_resdes_v1_options_proto_extTypes,
+	}.Build()
+	File_resdes_v1_options_proto = out.File
+	file_resdes_v1_options_proto_goTypes = nil
+	file_resdes_v1_options_proto_depIdxs = nil
+}
@@ -0,0 +1,166 @@
+package resdes
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	v1 "github.com/signal426/resdes/test_protos/gen/test_protos/resdes/v1"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+)
+
+func TestRecover(t *testing.T) {
+	serve := func(ctx context.Context, r *v1.CreateUserRequest) (*v1.CreateUserResponse, error) {
+		return &v1.CreateUserResponse{User: r.GetUser()}, nil
+	}
+	panicking := func(ctx context.Context, r *v1.CreateUserRequest) (*v1.CreateUserResponse, error) {
+		panic("boom")
+	}
+
+	t.Run("it should recover a panic in auth as an internal error of the auth stage", func(t *testing.T) {
+		// arrange
+		req := &v1.CreateUserRequest{User: &v1.User{Id: "abc123"}}
+
+		// act
+		_, err := Arrange[*v1.CreateUserRequest, *v1.CreateUserResponse]().
+			WithAuth(func(ctx context.Context, r *v1.CreateUserRequest) error {
+				panic("boom")
+			}).
+			WithServe(serve).
+			Exec(context.Background(), req)
+
+		// assert
+		var ierr *InternalError
+		assert.ErrorAs(t, err, &ierr)
+		assert.Equal(t, AuthStage, ierr.Stage)
+		assert.Equal(t, "boom", ierr.Value)
+		assert.NotEmpty(t, ierr.Stack)
+		assert.Equal(t, AuthStage, err.Stage())
+		assert.Nil(t, err.GetAuthError())
+	})
+
+	t.Run("it should recover a panic in a custom validation as an internal error of the validate stage", func(t *testing.T) {
+		// arrange
+		req := &v1.CreateUserRequest{User: &v1.User{Id: "abc123"}}
+
+		// act
+		_, err := Arrange[*v1.CreateUserRequest, *v1.CreateUserResponse]().
+			WithValidate(ForMessage[*v1.CreateUserRequest]().
+				CustomValidation(func(ctx context.Context, r *v1.CreateUserRequest, errs *ValidationErrors) error {
+					var seen map[string]bool
+					seen[r.GetUser().GetId()] = true
+					return nil
+				})).
+			WithServe(serve).
+			Exec(context.Background(), req)
+
+		// assert
+		assert.Equal(t, ValidateStage, err.Stage())
+		assert.NotNil(t, err.GetInternalError())
+		assert.Nil(t, err.GetValidationErrors())
+		// runtime errors are unwrapped from the internal error
+		var rerr interface{ RuntimeError() }
+		assert.ErrorAs(t, err, &rerr)
+	})
+
+	t.Run("it should recover a panic in serve as an internal error of the serve stage", func(t *testing.T) {
+		// arrange
+		req := &v1.CreateUserRequest{User: &v1.User{Id: "abc123"}}
+
+		// act
+		_, err := Arrange[*v1.CreateUserRequest, *v1.CreateUserResponse]().
+			WithServe(panicking).
+			Exec(context.Background(), req)
+
+		// assert
+		assert.Equal(t, ServeStage, err.Stage())
+		assert.Equal(t, "boom", err.GetInternalError().Value)
+		assert.Nil(t, err.GetServeError())
+	})
+
+	t.Run("it should recover a panic in stage middleware as an internal error of the stage", func(t *testing.T) {
+		// arrange
+		req := &v1.CreateUserRequest{User: &v1.User{Id: "abc123"}}
+
+		// act
+		_, err := Arrange[*v1.CreateUserRequest, *v1.CreateUserResponse]().
+			WithValidate(ForMessage[*v1.CreateUserRequest]().Require("user.id")).
+			WithServe(serve).
+			UseStage(func(ctx context.Context, stage Stage, msg *v1.CreateUserRequest, next StageFunc[*v1.CreateUserRequest, *v1.CreateUserResponse]) (*v1.CreateUserResponse, error) {
+				panic("boom")
+			}, ValidateStage).
+			Exec(context.Background(), req)
+
+		// assert
+		assert.Equal(t, ValidateStage, err.Stage())
+		assert.NotNil(t, err.GetInternalError())
+	})
+
+	t.Run("it should keep the stage of a recovered panic through execution middleware", func(t *testing.T) {
+		// arrange
+		req := &v1.CreateUserRequest{User: &v1.User{Id: "abc123"}}
+		var seen error
+
+		// act
+		_, err := Arrange[*v1.CreateUserRequest, *v1.CreateUserResponse]().
+			WithServe(panicking).
+			Use(func(ctx context.Context, stage Stage, msg *v1.CreateUserRequest, next StageFunc[*v1.CreateUserRequest, *v1.CreateUserResponse]) (*v1.CreateUserResponse, error) {
+				res, err := next(ctx, msg)
+				seen = err
+				return res, err
+			}).
+			Exec(context.Background(), req)
+
+		// assert
+		var ierr *InternalError
+		assert.ErrorAs(t, seen, &ierr)
+		assert.Equal(t, ServeStage, err.Stage())
+		assert.NotNil(t, err.GetInternalError())
+	})
+
+	t.Run("it should recover a panic in execution middleware as an internal error with no stage", func(t *testing.T) {
+		// arrange
+		req := &v1.CreateUserRequest{User: &v1.User{Id: "abc123"}}
+
+		// act
+		_, err := Arrange[*v1.CreateUserRequest, *v1.CreateUserResponse]().
+			WithServe(serve).
+			Use(func(ctx context.Context, stage Stage, msg *v1.CreateUserRequest, next StageFunc[*v1.CreateUserRequest, *v1.CreateUserResponse]) (*v1.CreateUserResponse, error) {
+				panic(errors.New("boom"))
+			}).
+			Exec(context.Background(), req)
+
+		// assert
+		assert.Equal(t, NoStage, err.Stage())
+		assert.NotNil(t, err.GetInternalError())
+		assert.Nil(t, err.GetServeError())
+		assert.EqualError(t, err, "panic in none stage: boom")
+	})
+
+	t.Run("it should convert an internal error to an internal status without the panic value", func(t *testing.T) {
+		// arrange
+		req := &v1.CreateUserRequest{User: &v1.User{Id: "abc123"}}
+		_, err := Arrange[*v1.CreateUserRequest, *v1.CreateUserResponse]().
+			WithServe(panicking).
+			Exec(context.Background(), req)
+
+		// act
+		s := err.ToGrpcStatus(WithServeCode(codes.Unknown))
+		back := FromGrpcStatus(s)
+
+		// assert
+		assert.Equal(t, codes.Internal, s.Code())
+		assert.NotContains(t, s.Message(), "boom")
+		for _, d := range s.Details() {
+			info, ok := d.(*errdetails.ErrorInfo)
+			assert.True(t, ok)
+			assert.Equal(t, "INTERNAL", info.GetReason())
+			assert.Equal(t, map[string]string{"stage": "serve"}, info.GetMetadata())
+		}
+		assert.Equal(t, ServeStage, back.Stage())
+		assert.NotNil(t, back.GetInternalError())
+		assert.Empty(t, back.GetInternalError().Stack)
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"google.golang.org/protobuf/proto"
 )
@@ -25,89 +26,193 @@ type MessageValidator[T proto.Message] interface {
 
 var _ MessageValidator[proto.Message] = (*DefaultMessageValidator[proto.Message])(nil)
 
+// MessageValidatorFunc adapts a function to a MessageValidator. Use it
+// to build a validator from the values of each message
+type MessageValidatorFunc[T proto.Message] func(context.Context, T) *ValidationErrors
+
+func (f MessageValidatorFunc[T]) Exec(ctx context.Context, message T) *ValidationErrors {
+	return f(ctx, message)
+}
+
 type DefaultMessageValidator[T proto.Message] struct {
-	// custom validation func. Only one can be set per validator instance
-	customValidation Validator[T]
+	// custom validation funcs, run in the order they were added
+	customValidations []namedValidator[T]
 
 	// paths is list of fields that are being evaluated if a field mask is supplied
 	paths map[string]struct{}
 
-	// fields to validate
-	fields []*Field
+	// mask paths as supplied, before normalization
+	mask []string
+
+	// rules to validate
+	rules []rule
+
+	// clock used by time policies, time.Now if not set
+	clock func() time.Time
 }
 
 // ForMessage creates a new DefaultMessageValidator
 // Accepts paths from a field mask if available
 func ForMessage[T proto.Message](fieldMask ...string) *DefaultMessageValidator[T] {
 	return &DefaultMessageValidator[T]{
-		paths:  GetPathsFromMask(fieldMask...),
-		fields: []*Field{},
+		paths: GetPathsFromMask(fieldMask...),
+		mask:  fieldMask,
+		rules: []rule{},
 	}
 }
 
 // AssertNonZero assert that the value for the supplied field path is not a zero-value
 func (s *DefaultMessageValidator[T]) AssertNonZero(path string, value any) *DefaultMessageValidator[T] {
-	s.fields = append(s.fields, NewField(path, value, NonZero, Always, nil, s.paths))
+	s.rules = append(s.rules, NewField(path, value, NonZero, Always, nil, s.paths))
 	return s
 }
 
 // AssertNotEqualTo assert that the value for the supplied field path is not equal to the supplied target value
 func (s *DefaultMessageValidator[T]) AssertNotEqualTo(path string, value any, notEqualTo any) *DefaultMessageValidator[T] {
-	s.fields = append(s.fields, NewField(path, value, NotEqualTo, Always, notEqualTo, s.paths))
+	s.rules = append(s.rules, NewField(path, value, NotEqualTo, Always, notEqualTo, s.paths))
 	return s
 }
 
 // AssertEqualTo assert that the value for the supplied fialed path is equal to the supplied target value
 func (s *DefaultMessageValidator[T]) AssertEqualTo(path string, value any, equalTo any) *DefaultMessageValidator[T] {
-	s.fields = append(s.fields, NewField(path, value, MustEqual, Always, equalTo, s.paths))
+	s.rules = append(s.rules, NewField(path, value, MustEqual, Always, equalTo, s.paths))
 	return s
 }
 
 // AssertNonZeroWhenInMask same as AssertNonZero, but only executes if the supplied path is in the field mask
 func (s *DefaultMessageValidator[T]) AssertNonZeroWhenInMask(path string, value any) *DefaultMessageValidator[T] {
-	s.fields = append(s.fields, NewField(path, value, NonZero, InMask, nil, s.paths))
+	s.rules = append(s.rules, NewField(path, value, NonZero, InMask, nil, s.paths))
 	return s
 }
 
 // AssertNotEqualToWhenInMask same as AssertNotEqualTo, but only executes if the supplied path is in the field mask
 func (s *DefaultMessageValidator[T]) AssertNotEqualToWhenInMask(path string, value any, notEqualTo any) *DefaultMessageValidator[T] {
-	s.fields = append(s.fields, NewField(path, value, NotEqualTo, InMask, notEqualTo, s.paths))
+	s.rules = append(s.rules, NewField(path, value, NotEqualTo, InMask, notEqualTo, s.paths))
 	return s
 }
 
 // AssertEqualToWhenInMask same as AssertEqualTo, but only executes if the supplied path is in the field mask
 func (s *DefaultMessageValidator[T]) AssertEqualToWhenInMask(path string, value any, equalTo any) *DefaultMessageValidator[T] {
-	s.fields = append(s.fields, NewField(path, value, MustEqual, InMask, equalTo, s.paths))
+	s.rules = append(s.rules, NewField(path, value, MustEqual, InMask, equalTo, s.paths))
 	return s
 }
 
-// CustomValidation is a custom validation function. There can only be one per-validator instance.
+// Require assert that the value at the supplied field path is not a zero-value.
+// The value is resolved from the message when the validator is executed.
+// Panics if the path does not exist in the message descriptor
+func (s *DefaultMessageValidator[T]) Require(path string) *DefaultMessageValidator[T] {
+	return s.require(path, NonZero, Always, nil)
+}
+
+// RequireNotEqualTo assert that the value at the supplied field path is not equal to the supplied target value.
+// The value is resolved from the message when the validator is executed.
+// Panics if the path does not exist in the message descriptor
+func (s *DefaultMessageValidator[T]) RequireNotEqualTo(path string, notEqualTo any) *DefaultMessageValidator[T] {
+	return s.require(path, NotEqualTo, Always, notEqualTo)
+}
+
+// RequireEqualTo assert that the value at the supplied field path is equal to the supplied target value.
+// The value is resolved from the message when the validator is executed.
+// Panics if the path does not exist in the message descriptor
+func (s *DefaultMessageValidator[T]) RequireEqualTo(path string, equalTo any) *DefaultMessageValidator[T] {
+	return s.require(path, MustEqual, Always, equalTo)
+}
+
+// RequireWhenInMask same as Require, but only executes if the supplied path is in the field mask
+func (s *DefaultMessageValidator[T]) RequireWhenInMask(path string) *DefaultMessageValidator[T] {
+	return s.require(path, NonZero, InMask, nil)
+}
+
+// RequireNotEqualToWhenInMask same as RequireNotEqualTo, but only executes if the supplied path is in the field mask
+func (s *DefaultMessageValidator[T]) RequireNotEqualToWhenInMask(path string, notEqualTo any) *DefaultMessageValidator[T] {
+	return s.require(path, NotEqualTo, InMask, notEqualTo)
+}
+
+// RequireEqualToWhenInMask same as RequireEqualTo, but only executes if the supplied path is in the field mask
+func (s *DefaultMessageValidator[T]) RequireEqualToWhenInMask(path string, equalTo any) *DefaultMessageValidator[T] {
+	return s.require(path, MustEqual, InMask, equalTo)
+}
+
+// ForEach runs the rules built by each on every element of the repeated or map field at the supplied path.
+// Errors are reported at the element path, e.g. user.secondary_addresses[2].line1 or labels["env"].
+// Panics if the path does not exist in the message descriptor or is not a repeated or map field
+func (s *DefaultMessageValidator[T]) ForEach(path string, each func(*EachRules)) *DefaultMessageValidator[T] {
+	var zero T
+	fds, err := resolvePath(zero.ProtoReflect().Descriptor(), path)
+	if err != nil {
+		panic(fmt.Sprintf("resdes: %v", err))
+	}
+	fd := fds[len(fds)-1]
+	rules := &EachRules{elem: fd}
+	switch {
+	case fd.IsMap():
+		rules.elem = fd.MapValue()
+	case !fd.IsList():
+		panic(fmt.Sprintf("resdes: field: %s is not a repeated or map field", path))
+	}
+	each(rules)
+	s.rules = append(s.rules, &eachRule{
+		path:  path,
+		fds:   fds,
+		rules: rules,
+		paths: s.paths,
+	})
+	return s
+}
+
+func (s *DefaultMessageValidator[T]) require(path string, policy Policy, condition Condition, cmpTo any) *DefaultMessageValidator[T] {
+	var zero T
+	fds, err := resolvePath(zero.ProtoReflect().Descriptor(), path)
+	if err != nil {
+		panic(fmt.Sprintf("resdes: %v", err))
+	}
+	s.rules = append(s.rules, newPathField(path, fds, policy, condition, cmpTo, s.paths))
+	return s
+}
+
+// namedValidator a custom validation function and the name its errors are tagged with
+type namedValidator[T proto.Message] struct {
+	name string
+	act  Validator[T]
+}
+
+// CustomValidation adds an unnamed custom validation function. Custom validations run in the order they were added.
 // To add field-level errors to the existing list of field validation errors (in the case regular Assertxxx functions are used),
 // add the errors to the ValidationErrors object and return nil.
 //
 // In the case that a non-field level error occurs, return the err
 func (s *DefaultMessageValidator[T]) CustomValidation(act Validator[T]) *DefaultMessageValidator[T] {
-	s.customValidation = act
+	return s.NamedCustomValidation("", act)
+}
+
+// NamedCustomValidation same as CustomValidation, but tags the field errors added by the function,
+// and the error it returns, with the supplied name. A nil function is skipped
+func (s *DefaultMessageValidator[T]) NamedCustomValidation(name string, act Validator[T]) *DefaultMessageValidator[T] {
+	if act == nil {
+		return s
+	}
+	s.customValidations = append(s.customValidations, namedValidator[T]{name: name, act: act})
 	return s
 }
 
 // Exec executes in the following order:
-// 1. Custom validation function if it exists
+// 1. Custom validation functions, in the order they were added
 // 2. Field-level assertion functions
 func (s *DefaultMessageValidator[T]) Exec(ctx context.Context, message T) *ValidationErrors {
 	errs := NewValidationErrors()
-	if s.customValidation != nil {
+	for _, cv := range s.customValidations {
+		errs.validator = cv.name
 		// if the validation error is simply returned, continue
-		if err := s.customValidation(ctx, message, errs); err != nil && !errors.Is(err, errs) {
-			errs.SetCustomValidationErr(fmt.Errorf("an error occurred during custom message validation: %w", err))
+		if err := cv.act(ctx, message, errs); err != nil && !errors.Is(err, errs) {
+			errs.AddCustomValidationErr(&CustomValidationError{Validator: cv.name, Err: err})
 		}
 	}
+	errs.validator = ""
 
-	if len(s.fields) > 0 {
-		for _, field := range s.fields {
-			if err := field.Validate(); err != nil {
-				errs.addFieldErr(field, err)
-			}
+	if len(s.rules) > 0 {
+		m := message.ProtoReflect()
+		for _, r := range s.rules {
+			r.validate(m, errs)
 		}
 	}
 
@@ -130,8 +235,38 @@ type Arrangement[T proto.Message, U any] struct {
 	// logic to run if all validations completed successfully --
 	// typically some business logic
 	Serve Server[T, U]
+
+	// validator to validate the result of Serve
+	ValidateResponse ResponseValidator[U]
+
+	// read mask to prune the result of Serve to, if U is a proto.Message
+	Project ReadMask[T]
+
+	// middleware wrapping the whole execution, in the order it was added
+	middleware []Middleware[T, U]
+
+	// middleware wrapping each stage, in the order it was added
+	stageMiddleware []stageMiddleware[T, U]
+
+	// timeout of each stage
+	timeouts map[Stage]time.Duration
+
+	// minimum time left before the request deadline to start Serve
+	minDeadline time.Duration
+
+	// policy to retry the Serve stage with
+	retry *RetryPolicy
+
+	// key and store to run the Serve stage once per idempotency key with
+	idempotency *idempotency[T, U]
+
+	// function to report ValidateResponse failures to instead of failing the request
+	logResponseValidation ResponseValidationLogger
 }
 
+// ReadMask a function returning the read mask paths of a request, e.g. (*v1.GetUserRequest).GetReadMask().GetPaths
+type ReadMask[T proto.Message] func(T) []string
+
 // Instantiate a new Arrangement to build
 func Arrange[T proto.Message, U any]() *Arrangement[T, U] {
 	return &Arrangement[T, U]{}
@@ -155,39 +290,162 @@ func (r *Arrangement[T, U]) WithServe(act Server[T, U]) *Arrangement[T, U] {
 	return r
 }
 
+// Add a ValidateResponse behavior. Pass the Exec method of a MessageValidator[U] if U is a proto.Message
+func (r *Arrangement[T, U]) WithValidateResponse(fv ResponseValidator[U]) *Arrangement[T, U] {
+	r.ValidateResponse = fv
+	return r
+}
+
+// Report ValidateResponse failures to log instead of failing the request, e.g. while rolling out response validation
+func (r *Arrangement[T, U]) WithResponseValidationLogOnly(log ResponseValidationLogger) *Arrangement[T, U] {
+	r.logResponseValidation = log
+	return r
+}
+
+// Add a Project behavior. The result of Serve is pruned to the fields in the read mask of the request
+func (r *Arrangement[T, U]) WithProjection(mask ReadMask[T]) *Arrangement[T, U] {
+	r.Project = mask
+	return r
+}
+
 // Exec runs in the following order:
 // 1. Auth
-// 2. Validate
-// 3. Serve
-// The function exits if any error is encountered at any stage
+// 2. Validate, including the paths of the read mask if Project is set
+// 3. Serve, at most once per idempotency key if WithIdempotency is set
+// 4. ValidateResponse
+// 5. Project
+// The function exits if any error is encountered at any stage. Middleware added with Use
+// wraps the whole execution, and middleware added with UseStage wraps each stage
 func (s *Arrangement[T, U]) Exec(ctx context.Context, message T) (U, *Error) {
-	// process the init action, if err, return
-	var res U
-	serr := &Error{}
-	if s.Auth != nil {
-		if err := s.Auth(ctx, message); err != nil {
-			serr.SetAuthError(err)
+	return s.exec(ctx, message, s.Serve)
+}
+
+// exec runs the stages with the supplied Serve stage
+func (s *Arrangement[T, U]) exec(ctx context.Context, message T, serve Server[T, U]) (U, *Error) {
+	run := chain(s.middleware, NoStage, func(ctx context.Context, message T) (U, error) {
+		var res U
+		if serr := s.guard(ctx, message); serr != nil {
 			return res, serr
 		}
+
+		// if no field faults, run success action
+		if serve != nil {
+			var serr *Error
+			if res, serr = s.idempotent(ctx, message, serve); serr != nil {
+				return res, serr
+			}
+		}
+
+		return s.project(message, res), nil
+	})
+
+	res, err := recoverStage(NoStage, run)(ctx, message)
+	if err == nil {
+		return res, nil
+	}
+	// errors returned by middleware that do not hold their stage are serve errors
+	var serr *Error
+	if errors.As(err, &serr) && (serr.Stage() != NoStage || serr.GetInternalError() != nil) {
+		return res, serr
 	}
+	return res, stageError(ServeStage, err)
+}
 
-	// validate fields if we have basic field validations
+// runServe runs the Serve stage once the request deadline has been checked, then the ValidateResponse stage
+func (s *Arrangement[T, U]) runServe(ctx context.Context, message T, serve Server[T, U]) (U, *Error) {
+	var zero U
+	if serr := s.checkDeadline(ctx); serr != nil {
+		return zero, serr
+	}
+	res, attempts, err := s.serveStage(ctx, message, serve)
+	if err != nil {
+		serr := stageError(ServeStage, err)
+		if se := serr.GetServeError(); se != nil {
+			se.Attempts = attempts
+		}
+		return res, serr
+	}
+	return res, s.validateResponse(ctx, message, res)
+}
+
+// guard runs the Auth and Validate stages
+func (s *Arrangement[T, U]) guard(ctx context.Context, message T) *Error {
+	if s.Auth != nil {
+		if _, err := s.stage(AuthStage, s.authStage)(ctx, message); err != nil {
+			return stageError(AuthStage, err)
+		}
+	}
 	if s.Validate != nil {
-		if err := s.Validate.Exec(ctx, message); err != nil {
-			serr.SetValidationErrors(err)
-			return res, serr
+		if _, err := s.stage(ValidateStage, s.validateStage)(ctx, message); err != nil {
+			return stageError(ValidateStage, err)
 		}
 	}
+	return s.validateReadMask(message)
+}
 
-	// if no field faults, run success action
-	if s.Serve != nil {
-		var err error
-		res, err = s.Serve(ctx, message)
-		if err != nil {
-			serr.SetServeError(err)
-			return res, serr
+// validateReadMask resolves the read mask paths of the request against the descriptor of U.
+// Invalid paths are reported on read_mask, whatever field the paths were read from
+func (s *Arrangement[T, U]) validateReadMask(message T) *Error {
+	var zero U
+	m, ok := any(zero).(proto.Message)
+	if s.Project == nil || !ok {
+		return nil
+	}
+	r := maskRule{path: defaultReadMaskField, md: m.ProtoReflect().Descriptor(), mask: s.Project(message)}
+	errs := NewValidationErrors()
+	r.validate(nil, errs)
+	if errs.HasErrors() {
+		serr := &Error{}
+		serr.SetValidationErrors(errs)
+		return serr
+	}
+	return nil
+}
+
+// project prunes the result to the read mask of the request. The read mask has already been validated
+func (s *Arrangement[T, U]) project(message T, res U) U {
+	m, ok := any(res).(proto.Message)
+	if s.Project == nil || !ok || !m.ProtoReflect().IsValid() {
+		return res
+	}
+	paths := s.Project(message)
+	if len(paths) == 0 {
+		return res
+	}
+	projected, err := Project(m, paths...)
+	if err != nil {
+		return res
+	}
+	return projected.(U)
+}
+
+func guard[T proto.Message](ctx context.Context, message T, auth Auther[T], validate MessageValidator[T]) (serr *Error) {
+	// a panic in a stage is returned as an internal error of the stage
+	stage := AuthStage
+	defer func() {
+		if v := recover(); v != nil {
+			serr = &Error{}
+			serr.SetInternalError(NewInternalError(stage, v))
+		}
+	}()
+
+	// process the init action, if err, return
+	serr = &Error{}
+	if auth != nil {
+		if err := auth(ctx, message); err != nil {
+			serr.SetAuthError(err)
+			return serr
 		}
 	}
 
-	return res, nil
+	// validate fields if we have basic field validations
+	stage = ValidateStage
+	if validate != nil {
+		if err := validate.Exec(ctx, message); err != nil {
+			serr.SetValidationErrors(err)
+			return serr
+		}
+	}
+
+	return nil
 }
@@ -96,6 +96,41 @@ func TestFieldValidations(t *testing.T) {
 		assert.Equal(t, expected.Error(), err.Error())
 	})
 
+	t.Run("it should assert string length in runes", func(t *testing.T) {
+		// arrange
+		req := &v1.CreateUserRequest{
+			User: &v1.User{
+				FirstName: "b",
+				LastName:  "Bøbsøn",
+			},
+		}
+		expected := &ValidationErrors{
+			FieldErrors: []*FieldError{
+				{
+					Path:   "user.first_name",
+					Policy: MinLength,
+					Err:    newFieldLengthTooShortErr("user.first_name", "b", 2),
+				},
+				{
+					Path:   "user.last_name",
+					Policy: MaxLength,
+					Err:    newFieldLengthTooLongErr("user.last_name", "Bøbsøn", 5),
+				},
+			},
+		}
+
+		// act
+		err := ForMessage[*v1.CreateUserRequest]().
+			AssertMinLength("user.first_name", req.GetUser().GetFirstName(), 2).
+			AssertMaxLength("user.last_name", req.GetUser().GetLastName(), 5).
+			AssertMaxLength("user.last_name", req.GetUser().GetLastName(), 6).
+			Exec(context.Background(), req)
+
+		// assert
+		assert.Error(t, err)
+		assert.Equal(t, expected.Error(), err.Error())
+	})
+
 	t.Run("it should capture custom validation errors", func(t *testing.T) {
 		// arrange
 		req := &v1.UpdateUserRequest{
@@ -142,6 +177,80 @@ func TestFieldValidations(t *testing.T) {
 		assert.Equal(t, expected.Error(), err.Error())
 	})
 
+	t.Run("it should run every named custom validation in order", func(t *testing.T) {
+		// arrange
+		req := &v1.CreateUserRequest{User: &v1.User{Id: "abc123"}}
+		var ran []string
+
+		// act
+		err := ForMessage[*v1.CreateUserRequest]().
+			NamedCustomValidation("reserved-ids", func(ctx context.Context, r *v1.CreateUserRequest, ve *ValidationErrors) error {
+				ran = append(ran, "reserved-ids")
+				ve.AddFieldErr("user.id", errors.New("user id is reserved"))
+				return nil
+			}).
+			NamedCustomValidation("directory", func(ctx context.Context, r *v1.CreateUserRequest, ve *ValidationErrors) error {
+				ran = append(ran, "directory")
+				return errors.New("lookup failed")
+			}).
+			CustomValidation(func(ctx context.Context, r *v1.CreateUserRequest, ve *ValidationErrors) error {
+				ran = append(ran, "")
+				ve.AddFieldErr("user.first_name", errors.New("first name is required"))
+				return errors.New("quota exceeded")
+			}).
+			Exec(context.Background(), req)
+
+		// assert
+		assert.Equal(t, []string{"reserved-ids", "directory", ""}, ran)
+		assert.Equal(t, "reserved-ids", err.AsMap()["user.id"].Validator)
+		assert.Equal(t, "", err.AsMap()["user.first_name"].Validator)
+		assert.Len(t, err.GetCustomValidationErrs(), 2)
+		var cve *CustomValidationError
+		assert.ErrorAs(t, err, &cve)
+		assert.Equal(t, "directory", cve.Validator)
+		assert.EqualError(t, err.GetCustomValidationErrs()[0], "an error occurred during custom message validation directory: lookup failed")
+		assert.EqualError(t, err.GetCustomValidationErrs()[1], "an error occurred during custom message validation: quota exceeded")
+	})
+
+	t.Run("it should keep the name of every validator that adds an error on a path", func(t *testing.T) {
+		// arrange
+		req := &v1.CreateUserRequest{User: &v1.User{Id: "abc123"}}
+		reserved := func(ctx context.Context, r *v1.CreateUserRequest, ve *ValidationErrors) error {
+			ve.AddFieldErr("user.id", errors.New("user id is reserved"))
+			return nil
+		}
+
+		// act
+		err := ForMessage[*v1.CreateUserRequest]().
+			NamedCustomValidation("reserved-ids", reserved).
+			NamedCustomValidation("directory", reserved).
+			Exec(context.Background(), req)
+
+		// assert
+		assert.Equal(t, []string{"user.id", "user.id"}, err.Paths())
+		assert.Equal(t, "reserved-ids", err.FieldErrors[0].Validator)
+		assert.Equal(t, "directory", err.FieldErrors[1].Validator)
+		assert.Equal(t, "reserved-ids", err.AsMap()["user.id"].Validator)
+	})
+
+	t.Run("it should skip a nil custom validation", func(t *testing.T) {
+		// arrange
+		req := &v1.CreateUserRequest{User: &v1.User{Id: "abc123"}}
+
+		// act
+		err := ForMessage[*v1.CreateUserRequest]().
+			CustomValidation(nil).
+			NamedCustomValidation("directory", nil).
+			Exec(context.Background(), req)
+		schemaErr := NewSchema[*v1.CreateUserRequest]().
+			CustomValidation(nil).
+			Exec(context.Background(), req)
+
+		// assert
+		assert.Nil(t, err)
+		assert.Nil(t, schemaErr)
+	})
+
 	t.Run("it should assert on field mask paths", func(t *testing.T) {
 		// arrange
 		req := &v1.UpdateUserRequest{
@@ -334,4 +443,289 @@ func TestArrangements(t *testing.T) {
 		inMap := ae.AsMap()["user.id"]
 		assert.NotNil(t, inMap)
 	})
+
+	t.Run("it should project the served response to the read mask", func(t *testing.T) {
+		// arrange
+		user := &v1.User{
+			Id:             "abc123",
+			FirstName:      "bob",
+			LastName:       "Bobson",
+			PrimaryAddress: &v1.Address{Line1: "1 Main St", Line2: "Apt 2"},
+		}
+		req := &v1.GetUserRequest{
+			Id:       "abc123",
+			ReadMask: &fieldmaskpb.FieldMask{Paths: []string{"id", "primaryAddress.line1"}},
+		}
+
+		// act
+		res, err := Arrange[*v1.GetUserRequest, *v1.User]().
+			WithServe(func(ctx context.Context, r *v1.GetUserRequest) (*v1.User, error) {
+				return user, nil
+			}).
+			WithProjection(func(r *v1.GetUserRequest) []string {
+				return r.GetReadMask().GetPaths()
+			}).
+			Exec(context.Background(), req)
+
+		// assert
+		assert.Nil(t, err)
+		assert.True(t, proto.Equal(&v1.User{Id: "abc123", PrimaryAddress: &v1.Address{Line1: "1 Main St"}}, res), "got %v", res)
+		assert.Equal(t, "Bobson", user.GetLastName())
+	})
+
+	t.Run("it should keep every field without a read mask", func(t *testing.T) {
+		// arrange
+		user := &v1.User{Id: "abc123", FirstName: "bob"}
+
+		// act
+		res, err := Arrange[*v1.GetUserRequest, *v1.User]().
+			WithServe(func(ctx context.Context, r *v1.GetUserRequest) (*v1.User, error) {
+				return user, nil
+			}).
+			WithProjection(func(r *v1.GetUserRequest) []string {
+				return r.GetReadMask().GetPaths()
+			}).
+			Exec(context.Background(), &v1.GetUserRequest{Id: "abc123"})
+
+		// assert
+		assert.Nil(t, err)
+		assert.Same(t, user, res)
+	})
+
+	t.Run("it should report invalid read mask paths as validation errors before serving", func(t *testing.T) {
+		// arrange
+		served := false
+		req := &v1.GetUserRequest{
+			Id:       "abc123",
+			ReadMask: &fieldmaskpb.FieldMask{Paths: []string{"first_name", "frist_name"}},
+		}
+
+		// act
+		_, err := Arrange[*v1.GetUserRequest, *v1.User]().
+			WithServe(func(ctx context.Context, r *v1.GetUserRequest) (*v1.User, error) {
+				served = true
+				return &v1.User{}, nil
+			}).
+			WithProjection(func(r *v1.GetUserRequest) []string {
+				return r.GetReadMask().GetPaths()
+			}).
+			Exec(context.Background(), req)
+
+		// assert
+		assert.False(t, served)
+		assert.Equal(t, ValidateStage, err.Stage())
+		assert.Equal(t, []string{"read_mask"}, err.GetValidationErrors().Paths())
+		assert.ErrorIs(t, err, ErrFieldPathNotFound)
+	})
+
+	t.Run("it should report invalid read mask paths on read_mask when the request has no read mask", func(t *testing.T) {
+		// arrange
+		req := &v1.UpdateUserRequest{
+			User:       &v1.User{Id: "abc123"},
+			UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"user.nope"}},
+		}
+
+		// act
+		_, err := Arrange[*v1.UpdateUserRequest, *v1.UpdateUserResponse]().
+			WithServe(func(ctx context.Context, r *v1.UpdateUserRequest) (*v1.UpdateUserResponse, error) {
+				return &v1.UpdateUserResponse{}, nil
+			}).
+			WithProjection(func(r *v1.UpdateUserRequest) []string {
+				return r.GetUpdateMask().GetPaths()
+			}).
+			Exec(context.Background(), req)
+
+		// assert
+		assert.Equal(t, []string{"read_mask"}, err.GetValidationErrors().Paths())
+	})
+}
+
+func TestPathValidations(t *testing.T) {
+	t.Run("it should resolve values from the message", func(t *testing.T) {
+		// arrange
+		req := &v1.UpdateUserRequest{
+			User: &v1.User{
+				FirstName: "bob",
+			},
+			UpdateMask: &fieldmaskpb.FieldMask{
+				Paths: []string{"user.firstName", "user.primaryAddress.line1"},
+			},
+		}
+		expected := &ValidationErrors{
+			FieldErrors: []*FieldError{
+				{
+					Path:   "user.id",
+					Policy: NonZero,
+					Err:    newFieldMustNotBeZeroFailedErr("user.id", ""),
+				},
+				{
+					Path:   "user.first_name",
+					Policy: NotEqualTo,
+					Err:    newFieldMustNotEqualFailedErr("user.first_name", "bob"),
+				},
+				{
+					Path:   "user.primary_address.line1",
+					Policy: NonZero,
+					Err:    newFieldMustNotBeZeroFailedErr("user.primary_address.line1", ""),
+				},
+			},
+		}
+
+		// act
+		err := ForMessage[*v1.UpdateUserRequest](req.GetUpdateMask().GetPaths()...).
+			Require("user").
+			Require("user.id").
+			RequireNotEqualToWhenInMask("user.first_name", "bob").
+			RequireWhenInMask("user.last_name").
+			RequireWhenInMask("user.primary_address.line1").
+			Exec(context.Background(), req)
+
+		// assert
+		assert.Error(t, err)
+		assert.Equal(t, expected.Error(), err.Error())
+	})
+
+	t.Run("it should report unset messages as zero", func(t *testing.T) {
+		// arrange
+		req := &v1.CreateUserRequest{}
+
+		// act
+		err := ForMessage[*v1.CreateUserRequest]().
+			Require("user").
+			RequireEqualTo("user.primaryAddress.line1", "a").
+			Exec(context.Background(), req)
+
+		// assert
+		assert.Error(t, err)
+		assert.Equal(t, []string{"user", "user.primaryAddress.line1"}, err.Paths())
+		assert.ErrorIs(t, err.AsMap()["user"], ErrFieldMustNotBeZeroFailed)
+		assert.ErrorIs(t, err.AsMap()["user.primaryAddress.line1"], ErrFieldMustEqualFailed)
+	})
+
+	t.Run("it should reuse the validator across messages", func(t *testing.T) {
+		// arrange
+		validator := ForMessage[*v1.CreateUserRequest]().
+			Require("user.id")
+
+		// act
+		invalid := validator.Exec(context.Background(), &v1.CreateUserRequest{})
+		valid := validator.Exec(context.Background(), &v1.CreateUserRequest{User: &v1.User{Id: "abc123"}})
+
+		// assert
+		assert.Error(t, invalid)
+		assert.Nil(t, valid)
+	})
+
+	t.Run("it should panic if the path does not exist", func(t *testing.T) {
+		// assert
+		assert.PanicsWithValue(t, "resdes: "+newFieldPathNotFoundErr("user.frist_name", "frist_name").Error(), func() {
+			ForMessage[*v1.CreateUserRequest]().Require("user.frist_name")
+		})
+		assert.Panics(t, func() {
+			ForMessage[*v1.CreateUserRequest]().Require("user.id.value")
+		})
+	})
+}
+
+func TestForEachValidations(t *testing.T) {
+	t.Run("it should report errors at indexed element paths", func(t *testing.T) {
+		// arrange
+		req := &v1.CreateUserRequest{
+			User: &v1.User{
+				SecondaryAddresses: []*v1.Address{
+					{Line1: "a", Line2: "b"},
+					{Line2: "b"},
+					{Line1: "a", Line2: "c"},
+				},
+			},
+		}
+		expected := &ValidationErrors{
+			FieldErrors: []*FieldError{
+				{
+					Path:   "user.secondary_addresses[1].line1",
+					Policy: NonZero,
+					Err:    newFieldMustNotBeZeroFailedErr("user.secondary_addresses[1].line1", ""),
+				},
+				{
+					Path:   "user.secondary_addresses[2].line2",
+					Policy: MustEqual,
+					Err:    newFieldMustEqualFailedErr("user.secondary_addresses[2].line2", "b", "c"),
+				},
+			},
+		}
+
+		// act
+		err := ForMessage[*v1.CreateUserRequest]().
+			ForEach("user.secondary_addresses", func(each *EachRules) {
+				each.Require("").
+					Require("line1").
+					RequireEqualTo("line2", "b")
+			}).
+			Exec(context.Background(), req)
+
+		// assert
+		assert.Error(t, err)
+		assert.Equal(t, expected.Error(), err.Error())
+	})
+
+	t.Run("it should report errors at map entry paths", func(t *testing.T) {
+		// arrange
+		req := &v1.CreateUserRequest{
+			User: &v1.User{
+				Labels: map[string]string{
+					"team": "",
+					"env":  "prod",
+					"app":  "",
+				},
+			},
+		}
+
+		// act
+		err := ForMessage[*v1.CreateUserRequest]().
+			ForEach("user.labels", func(each *EachRules) {
+				each.Require("")
+			}).
+			Exec(context.Background(), req)
+
+		// assert
+		assert.Error(t, err)
+		assert.Equal(t, []string{`user.labels["app"]`, `user.labels["team"]`}, err.Paths())
+	})
+
+	t.Run("it should run in mask element rules when the repeated field is in the mask", func(t *testing.T) {
+		// arrange
+		req := &v1.UpdateUserRequest{
+			User: &v1.User{
+				SecondaryAddresses: []*v1.Address{{}},
+			},
+		}
+
+		// act
+		inMask := ForMessage[*v1.UpdateUserRequest]("user.secondary_addresses").
+			ForEach("user.secondary_addresses", func(each *EachRules) {
+				each.RequireWhenInMask("line1")
+			}).
+			Exec(context.Background(), req)
+		notInMask := ForMessage[*v1.UpdateUserRequest]("user.first_name").
+			ForEach("user.secondary_addresses", func(each *EachRules) {
+				each.RequireWhenInMask("line1")
+			}).
+			Exec(context.Background(), req)
+
+		// assert
+		assert.Equal(t, []string{"user.secondary_addresses[0].line1"}, inMask.Paths())
+		assert.Nil(t, notInMask)
+	})
+
+	t.Run("it should panic if the path is not a repeated or map field", func(t *testing.T) {
+		// assert
+		assert.Panics(t, func() {
+			ForMessage[*v1.CreateUserRequest]().ForEach("user.primary_address", func(*EachRules) {})
+		})
+		assert.Panics(t, func() {
+			ForMessage[*v1.CreateUserRequest]().ForEach("user.labels", func(each *EachRules) {
+				each.Require("line1")
+			})
+		})
+	})
 }
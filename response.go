@@ -0,0 +1,31 @@
+package resdes
+
+import "context"
+
+// ResponseValidator a function validating the result of Serve, e.g. the Exec method of a MessageValidator[U]
+type ResponseValidator[U any] func(context.Context, U) *ValidationErrors
+
+// ResponseValidationLogger a function called with the failures of the ValidateResponse stage in log-only mode
+type ResponseValidationLogger func(context.Context, *ResponseValidationError)
+
+// validateResponse runs the ValidateResponse stage on the result of Serve. In log-only mode
+// every failure of the stage, including panics and timeouts, is reported to the logger
+func (r *Arrangement[T, U]) validateResponse(ctx context.Context, message T, res U) *Error {
+	if r.ValidateResponse == nil {
+		return nil
+	}
+	_, err := r.stage(ValidateResponseStage, func(ctx context.Context, _ T) (U, error) {
+		if errs := r.ValidateResponse(ctx, res); errs != nil {
+			return res, errs
+		}
+		return res, nil
+	})(ctx, message)
+	if err == nil {
+		return nil
+	}
+	if r.logResponseValidation == nil {
+		return stageError(ValidateResponseStage, err)
+	}
+	r.logResponseValidation(ctx, &ResponseValidationError{Errs: asValidationErrors(err)})
+	return nil
+}
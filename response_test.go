@@ -0,0 +1,157 @@
+package resdes
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	v1 "github.com/signal426/resdes/test_protos/gen/test_protos/resdes/v1"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+)
+
+func TestValidateResponse(t *testing.T) {
+	responseValidator := ForMessage[*v1.CreateUserResponse]().Require("user.id")
+	serveEmpty := func(ctx context.Context, r *v1.CreateUserRequest) (*v1.CreateUserResponse, error) {
+		return &v1.CreateUserResponse{User: &v1.User{}}, nil
+	}
+
+	t.Run("it should pass a valid response", func(t *testing.T) {
+		// arrange
+		req := &v1.CreateUserRequest{User: &v1.User{Id: "abc123"}}
+
+		// act
+		res, err := Arrange[*v1.CreateUserRequest, *v1.CreateUserResponse]().
+			WithServe(func(ctx context.Context, r *v1.CreateUserRequest) (*v1.CreateUserResponse, error) {
+				return &v1.CreateUserResponse{User: r.GetUser()}, nil
+			}).
+			WithValidateResponse(responseValidator.Exec).
+			Exec(context.Background(), req)
+
+		// assert
+		assert.Nil(t, err)
+		assert.Equal(t, "abc123", res.GetUser().GetId())
+	})
+
+	t.Run("it should return a response validation error for an invalid response", func(t *testing.T) {
+		// arrange
+		req := &v1.CreateUserRequest{User: &v1.User{Id: "abc123"}}
+
+		// act
+		_, err := Arrange[*v1.CreateUserRequest, *v1.CreateUserResponse]().
+			WithServe(serveEmpty).
+			WithValidateResponse(responseValidator.Exec).
+			Exec(context.Background(), req)
+
+		// assert
+		var rerr *ResponseValidationError
+		assert.ErrorAs(t, err, &rerr)
+		assert.Equal(t, ValidateResponseStage, err.Stage())
+		assert.Nil(t, err.GetValidationErrors())
+		assert.Nil(t, err.GetServeError())
+		assert.ErrorIs(t, err, ErrFieldMustNotBeZeroFailed)
+		assert.Equal(t, []string{"user.id"}, rerr.Errs.Paths())
+	})
+
+	t.Run("it should not blame the caller for an invalid response", func(t *testing.T) {
+		// arrange
+		req := &v1.CreateUserRequest{User: &v1.User{Id: "abc123"}}
+		_, err := Arrange[*v1.CreateUserRequest, *v1.CreateUserResponse]().
+			WithServe(serveEmpty).
+			WithValidateResponse(responseValidator.Exec).
+			Exec(context.Background(), req)
+
+		// act
+		s := err.ToGrpcStatus()
+		back := FromGrpcStatus(s)
+
+		// assert
+		assert.Equal(t, codes.Internal, s.Code())
+		assert.NotContains(t, s.Message(), "user.id")
+		assert.Equal(t, ValidateResponseStage, back.Stage())
+		assert.NotNil(t, back.GetResponseValidationError())
+		assert.Nil(t, back.GetValidationErrors())
+	})
+
+	t.Run("it should only report an invalid response in log-only mode", func(t *testing.T) {
+		// arrange
+		req := &v1.CreateUserRequest{User: &v1.User{Id: "abc123"}}
+		var logged []*ResponseValidationError
+
+		// act
+		res, err := Arrange[*v1.CreateUserRequest, *v1.CreateUserResponse]().
+			WithServe(serveEmpty).
+			WithValidateResponse(responseValidator.Exec).
+			WithResponseValidationLogOnly(func(ctx context.Context, rerr *ResponseValidationError) {
+				logged = append(logged, rerr)
+			}).
+			Exec(context.Background(), req)
+
+		// assert
+		assert.Nil(t, err)
+		assert.NotNil(t, res)
+		assert.Len(t, logged, 1)
+		assert.Equal(t, []string{"user.id"}, logged[0].Errs.Paths())
+	})
+
+	t.Run("it should report a panic in the response validator in log-only mode", func(t *testing.T) {
+		// arrange
+		req := &v1.CreateUserRequest{User: &v1.User{Id: "abc123"}}
+		var logged *ResponseValidationError
+
+		// act
+		_, err := Arrange[*v1.CreateUserRequest, *v1.CreateUserResponse]().
+			WithServe(serveEmpty).
+			WithValidateResponse(func(ctx context.Context, res *v1.CreateUserResponse) *ValidationErrors {
+				panic("boom")
+			}).
+			WithResponseValidationLogOnly(func(ctx context.Context, rerr *ResponseValidationError) {
+				logged = rerr
+			}).
+			Exec(context.Background(), req)
+
+		// assert
+		assert.Nil(t, err)
+		var ierr *InternalError
+		assert.ErrorAs(t, logged, &ierr)
+		assert.Equal(t, ValidateResponseStage, ierr.Stage)
+	})
+
+	t.Run("it should not run response validation when serve fails", func(t *testing.T) {
+		// arrange
+		req := &v1.CreateUserRequest{User: &v1.User{Id: "abc123"}}
+		validated := false
+
+		// act
+		_, err := Arrange[*v1.CreateUserRequest, *v1.CreateUserResponse]().
+			WithServe(func(ctx context.Context, r *v1.CreateUserRequest) (*v1.CreateUserResponse, error) {
+				return nil, errors.New("boom")
+			}).
+			WithValidateResponse(func(ctx context.Context, res *v1.CreateUserResponse) *ValidationErrors {
+				validated = true
+				return nil
+			}).
+			Exec(context.Background(), req)
+
+		// assert
+		assert.NotNil(t, err.GetServeError())
+		assert.False(t, validated)
+	})
+
+	t.Run("it should wrap the response validation stage with stage middleware", func(t *testing.T) {
+		// arrange
+		var calls []string
+		req := &v1.CreateUserRequest{User: &v1.User{Id: "abc123"}}
+
+		// act
+		_, err := Arrange[*v1.CreateUserRequest, *v1.CreateUserResponse]().
+			WithServe(serveEmpty).
+			WithValidateResponse(responseValidator.Exec).
+			UseStage(recordingMiddleware("mw", &calls), ValidateResponseStage).
+			Exec(context.Background(), req)
+
+		// assert
+		assert.NotNil(t, err.GetResponseValidationError())
+		assert.Equal(t, []string{"mw before validate_response", "mw after validate_response: true"}, calls)
+	})
+}
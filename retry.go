@@ -0,0 +1,151 @@
+package resdes
+
+import (
+	"context"
+	"math"
+	"math/rand/v2"
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// AttemptsMetadataKey the response trailer key holding the number of Serve attempts of a gRPC request
+// served by an arrangement with a retry policy
+const AttemptsMetadataKey = "resdes-serve-attempts"
+
+// RetryClassifier reports whether a failed Serve attempt can be retried
+type RetryClassifier func(error) bool
+
+// Sleeper waits for the duration, returning the error of the context if it is done first.
+// Replace it with a fake clock to test retries without waiting
+type Sleeper func(context.Context, time.Duration) error
+
+// RetryPolicy retries the Serve stage of an arrangement on transient errors, waiting an
+// exponential backoff with jitter between attempts
+type RetryPolicy struct {
+	maxAttempts    int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	multiplier     float64
+	jitter         float64
+	retryable      RetryClassifier
+	sleep          Sleeper
+	rand           func() float64
+}
+
+// NewRetryPolicy creates a policy making at most maxAttempts attempts, retrying errors classified by
+// IsRetryable after a backoff starting at 100ms, doubling up to 5s, with 20% jitter
+func NewRetryPolicy(maxAttempts int) *RetryPolicy {
+	return &RetryPolicy{
+		maxAttempts:    maxAttempts,
+		initialBackoff: 100 * time.Millisecond,
+		maxBackoff:     5 * time.Second,
+		multiplier:     2,
+		jitter:         0.2,
+		retryable:      IsRetryable,
+		sleep:          sleep,
+		rand:           rand.Float64,
+	}
+}
+
+// WithBackoff sets the backoff before the first retry, and the most it can grow to
+func (p *RetryPolicy) WithBackoff(initial, max time.Duration) *RetryPolicy {
+	p.initialBackoff = initial
+	p.maxBackoff = max
+	return p
+}
+
+// WithMultiplier sets the factor the backoff grows by after each retry
+func (p *RetryPolicy) WithMultiplier(multiplier float64) *RetryPolicy {
+	p.multiplier = multiplier
+	return p
+}
+
+// WithJitter sets the fraction of the backoff it is randomly moved by in either direction, from 0 to 1
+func (p *RetryPolicy) WithJitter(fraction float64) *RetryPolicy {
+	p.jitter = fraction
+	return p
+}
+
+// WithRetryable sets the classifier of the errors to retry
+func (p *RetryPolicy) WithRetryable(retryable RetryClassifier) *RetryPolicy {
+	p.retryable = retryable
+	return p
+}
+
+// WithSleeper sets how the policy waits between attempts
+func (p *RetryPolicy) WithSleeper(sleep Sleeper) *RetryPolicy {
+	p.sleep = sleep
+	return p
+}
+
+// IsRetryable reports whether the error holds a gRPC status that is transient: Unavailable or Aborted
+func IsRetryable(err error) bool {
+	s, ok := status.FromError(err)
+	if !ok {
+		return false
+	}
+	switch s.Code() {
+	case codes.Unavailable, codes.Aborted:
+		return true
+	}
+	return false
+}
+
+// backoff returns the time to wait after the supplied attempt failed
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	d := float64(p.initialBackoff) * math.Pow(p.multiplier, float64(attempt-1))
+	d = math.Min(d, float64(p.maxBackoff))
+	d += d * p.jitter * (2*p.rand() - 1)
+	return time.Duration(math.Max(d, 0))
+}
+
+// retry runs the Serve stage until it succeeds, fails with an error that cannot be retried, runs out of
+// attempts or the context is done, returning the result and error of the last attempt and the number of attempts
+func retry[T proto.Message, U any](ctx context.Context, p *RetryPolicy, message T, serve StageFunc[T, U]) (U, int, error) {
+	for attempt := 1; ; attempt++ {
+		res, err := serve(ctx, message)
+		if err == nil || attempt >= p.maxAttempts || !p.retryable(err) {
+			return res, attempt, err
+		}
+		if p.sleep(ctx, p.backoff(attempt)) != nil {
+			return res, attempt, err
+		}
+	}
+}
+
+// WithRetry retries the Serve stage with the policy. Each attempt runs the Serve stage middleware and
+// timeout. The number of attempts is set on the ServeErr and, for gRPC requests, the response trailer
+func (r *Arrangement[T, U]) WithRetry(policy *RetryPolicy) *Arrangement[T, U] {
+	r.retry = policy
+	return r
+}
+
+// serveStage runs the Serve stage, retrying it if the arrangement has a retry policy
+func (r *Arrangement[T, U]) serveStage(ctx context.Context, message T, serve Server[T, U]) (U, int, error) {
+	run := r.stage(ServeStage, StageFunc[T, U](serve))
+	if r.retry == nil {
+		res, err := run(ctx, message)
+		return res, 0, err
+	}
+	res, attempts, err := retry(ctx, r.retry, message, run)
+	// fails outside of a gRPC server
+	_ = grpc.SetTrailer(ctx, metadata.Pairs(AttemptsMetadataKey, strconv.Itoa(attempts)))
+	return res, attempts, err
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
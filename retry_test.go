@@ -0,0 +1,211 @@
+package resdes
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	v1 "github.com/signal426/resdes/test_protos/gen/test_protos/resdes/v1"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// fakeClock records the waits of a retry policy without sleeping, and cancels
+// the context after the supplied number of waits if cancel is set
+type fakeClock struct {
+	waits  []time.Duration
+	after  int
+	cancel context.CancelFunc
+}
+
+func (c *fakeClock) sleep(ctx context.Context, d time.Duration) error {
+	c.waits = append(c.waits, d)
+	if c.cancel != nil && len(c.waits) >= c.after {
+		c.cancel()
+	}
+	return ctx.Err()
+}
+
+// trailerStream records the trailer set by the arrangement
+type trailerStream struct {
+	grpc.ServerTransportStream
+	trailer metadata.MD
+}
+
+func (s *trailerStream) SetTrailer(md metadata.MD) error {
+	s.trailer = metadata.Join(s.trailer, md)
+	return nil
+}
+
+// flakyServe fails with the error until it has been called the supplied number of times
+func flakyServe(calls *int, failures int, err error) Server[*v1.CreateUserRequest, *v1.CreateUserResponse] {
+	return func(ctx context.Context, r *v1.CreateUserRequest) (*v1.CreateUserResponse, error) {
+		*calls++
+		if *calls <= failures {
+			return nil, err
+		}
+		return &v1.CreateUserResponse{User: r.GetUser()}, nil
+	}
+}
+
+func TestRetry(t *testing.T) {
+	unavailable := status.Error(codes.Unavailable, "try again")
+
+	t.Run("it should retry transient errors with exponential backoff", func(t *testing.T) {
+		// arrange
+		req := &v1.CreateUserRequest{User: &v1.User{Id: "abc123"}}
+		clock := &fakeClock{}
+		var calls int
+
+		// act
+		res, err := Arrange[*v1.CreateUserRequest, *v1.CreateUserResponse]().
+			WithServe(flakyServe(&calls, 3, unavailable)).
+			WithRetry(NewRetryPolicy(5).
+				WithBackoff(10*time.Millisecond, 30*time.Millisecond).
+				WithJitter(0).
+				WithSleeper(clock.sleep)).
+			Exec(context.Background(), req)
+
+		// assert
+		assert.Nil(t, err)
+		assert.Equal(t, "abc123", res.GetUser().GetId())
+		assert.Equal(t, 4, calls)
+		assert.Equal(t, []time.Duration{10 * time.Millisecond, 20 * time.Millisecond, 30 * time.Millisecond}, clock.waits)
+	})
+
+	t.Run("it should record the attempts on the serve error when attempts run out", func(t *testing.T) {
+		// arrange
+		req := &v1.CreateUserRequest{User: &v1.User{Id: "abc123"}}
+		clock := &fakeClock{}
+		var calls int
+
+		// act
+		_, err := Arrange[*v1.CreateUserRequest, *v1.CreateUserResponse]().
+			WithServe(flakyServe(&calls, 5, unavailable)).
+			WithRetry(NewRetryPolicy(3).WithSleeper(clock.sleep)).
+			Exec(context.Background(), req)
+
+		// assert
+		assert.Equal(t, 3, calls)
+		assert.Len(t, clock.waits, 2)
+		assert.Equal(t, 3, err.GetServeError().Attempts)
+		assert.Equal(t, codes.Unavailable, status.Code(err.GetServeError().Err))
+	})
+
+	t.Run("it should not retry errors that are not retryable", func(t *testing.T) {
+		// arrange
+		req := &v1.CreateUserRequest{User: &v1.User{Id: "abc123"}}
+		clock := &fakeClock{}
+		var calls int
+
+		// act
+		_, err := Arrange[*v1.CreateUserRequest, *v1.CreateUserResponse]().
+			WithServe(flakyServe(&calls, 5, status.Error(codes.NotFound, "missing"))).
+			WithRetry(NewRetryPolicy(3).WithSleeper(clock.sleep)).
+			Exec(context.Background(), req)
+
+		// assert
+		assert.Equal(t, 1, calls)
+		assert.Empty(t, clock.waits)
+		assert.Equal(t, 1, err.GetServeError().Attempts)
+	})
+
+	t.Run("it should retry the errors of a custom classifier", func(t *testing.T) {
+		// arrange
+		req := &v1.CreateUserRequest{User: &v1.User{Id: "abc123"}}
+		errFlaky := errors.New("flaky")
+		var calls int
+
+		// act
+		_, err := Arrange[*v1.CreateUserRequest, *v1.CreateUserResponse]().
+			WithServe(flakyServe(&calls, 1, errFlaky)).
+			WithRetry(NewRetryPolicy(3).
+				WithRetryable(func(err error) bool { return errors.Is(err, errFlaky) }).
+				WithSleeper((&fakeClock{}).sleep)).
+			Exec(context.Background(), req)
+
+		// assert
+		assert.Nil(t, err)
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("it should stop retrying when the context is done", func(t *testing.T) {
+		// arrange
+		req := &v1.CreateUserRequest{User: &v1.User{Id: "abc123"}}
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		clock := &fakeClock{after: 2, cancel: cancel}
+		var calls int
+
+		// act
+		_, err := Arrange[*v1.CreateUserRequest, *v1.CreateUserResponse]().
+			WithServe(flakyServe(&calls, 5, unavailable)).
+			WithRetry(NewRetryPolicy(5).WithSleeper(clock.sleep)).
+			Exec(ctx, req)
+
+		// assert
+		assert.Equal(t, 2, calls)
+		assert.Equal(t, 2, err.GetServeError().Attempts)
+		assert.Equal(t, codes.Unavailable, status.Code(err.GetServeError().Err))
+	})
+
+	t.Run("it should set the attempts in the response trailer", func(t *testing.T) {
+		// arrange
+		req := &v1.CreateUserRequest{User: &v1.User{Id: "abc123"}}
+		stream := &trailerStream{}
+		ctx := grpc.NewContextWithServerTransportStream(context.Background(), stream)
+		var calls int
+
+		// act
+		_, err := Arrange[*v1.CreateUserRequest, *v1.CreateUserResponse]().
+			WithServe(flakyServe(&calls, 1, unavailable)).
+			WithRetry(NewRetryPolicy(3).WithSleeper((&fakeClock{}).sleep)).
+			Exec(ctx, req)
+
+		// assert
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"2"}, stream.trailer.Get(AttemptsMetadataKey))
+	})
+
+	t.Run("it should keep the attempts through a grpc status", func(t *testing.T) {
+		// arrange
+		serr := &Error{}
+		serr.SetServeError(unavailable)
+		serr.GetServeError().Attempts = 3
+
+		// act
+		back := FromError(serr.ToGrpcStatus().Err())
+
+		// assert
+		assert.Equal(t, codes.Unavailable, status.Code(back.GetServeError().Err))
+		assert.Equal(t, 3, back.GetServeError().Attempts)
+	})
+}
+
+func TestRetryBackoff(t *testing.T) {
+	t.Run("it should move the backoff by the jitter in either direction", func(t *testing.T) {
+		// arrange
+		policy := NewRetryPolicy(3).WithBackoff(100*time.Millisecond, time.Second).WithJitter(0.5)
+
+		// act
+		policy.rand = func() float64 { return 0 }
+		low := policy.backoff(1)
+		policy.rand = func() float64 { return 1 }
+		high := policy.backoff(2)
+
+		// assert
+		assert.Equal(t, 50*time.Millisecond, low)
+		assert.Equal(t, 300*time.Millisecond, high)
+	})
+
+	t.Run("it should classify transient grpc statuses as retryable", func(t *testing.T) {
+		assert.True(t, IsRetryable(status.Error(codes.Unavailable, "")))
+		assert.True(t, IsRetryable(status.Error(codes.Aborted, "")))
+		assert.False(t, IsRetryable(status.Error(codes.InvalidArgument, "")))
+		assert.False(t, IsRetryable(errors.New("boom")))
+	})
+}
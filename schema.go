@@ -0,0 +1,201 @@
+package resdes
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// Schema is a set of rules built once, e.g. at startup, and executed against every message.
+// Values are extracted from the message, and the field mask supplied, when the schema is executed.
+// A schema must not be modified once it is in use, after which it is safe for concurrent use
+type Schema[T proto.Message] struct {
+	// custom validation funcs, run in the order they were added
+	customValidations []namedValidator[T]
+
+	// rules to validate, in the order they were added
+	rules []schemaRule[T]
+
+	// path field mask errors are reported on, set if the mask is validated
+	maskField string
+}
+
+// schemaRule is evaluated against a message and the paths of its field mask
+type schemaRule[T proto.Message] interface {
+	validate(message T, paths map[string]struct{}, errs *ValidationErrors)
+}
+
+// NewSchema creates a new Schema
+func NewSchema[T proto.Message]() *Schema[T] {
+	return &Schema[T]{}
+}
+
+// CustomValidation same as DefaultMessageValidator.CustomValidation
+func (s *Schema[T]) CustomValidation(act Validator[T]) *Schema[T] {
+	return s.NamedCustomValidation("", act)
+}
+
+// NamedCustomValidation same as DefaultMessageValidator.NamedCustomValidation
+func (s *Schema[T]) NamedCustomValidation(name string, act Validator[T]) *Schema[T] {
+	if act == nil {
+		return s
+	}
+	s.customValidations = append(s.customValidations, namedValidator[T]{name: name, act: act})
+	return s
+}
+
+// ValidateMask same as DefaultMessageValidator.ValidateMask, for the field mask supplied to Exec
+func (s *Schema[T]) ValidateMask() *Schema[T] {
+	var zero T
+	s.maskField = updateMaskFieldName(zero.ProtoReflect().Descriptor())
+	return s
+}
+
+// Exec executes in the following order:
+// 1. Custom validation functions, in the order they were added
+// 2. Field mask paths, if ValidateMask is set
+// 3. Field-level rules, extracting each value from the message
+// Rules with the InMask condition are evaluated against the supplied field mask paths
+func (s *Schema[T]) Exec(ctx context.Context, message T, mask ...string) *ValidationErrors {
+	errs := &ValidationErrors{}
+	for _, cv := range s.customValidations {
+		errs.validator = cv.name
+		// if the validation error is simply returned, continue
+		if err := cv.act(ctx, message, errs); err != nil && !errors.Is(err, errs) {
+			errs.AddCustomValidationErr(&CustomValidationError{Validator: cv.name, Err: err})
+		}
+	}
+	errs.validator = ""
+
+	if s.maskField != "" {
+		var zero T
+		r := maskRule{path: s.maskField, md: zero.ProtoReflect().Descriptor(), mask: mask}
+		r.validate(nil, errs)
+	}
+
+	var paths map[string]struct{}
+	if len(mask) > 0 {
+		paths = GetPathsFromMask(mask...)
+	}
+	for _, r := range s.rules {
+		r.validate(message, paths, errs)
+	}
+
+	if errs.HasErrors() {
+		return errs
+	}
+
+	return nil
+}
+
+// Validator returns a MessageValidator that executes the schema with the field mask
+// paths returned by mask, e.g. req.GetUpdateMask().GetPaths. The mask may be nil
+func (s *Schema[T]) Validator(mask func(T) []string) MessageValidator[T] {
+	return MessageValidatorFunc[T](func(ctx context.Context, message T) *ValidationErrors {
+		if mask == nil {
+			return s.Exec(ctx, message)
+		}
+		return s.Exec(ctx, message, mask(message)...)
+	})
+}
+
+// SchemaField binds a path of a schema to a function extracting its value from the message,
+// e.g. (*v1.User).GetId. Rules added to the field apply to the extracted value
+type SchemaField[T proto.Message, V any] struct {
+	schema         *Schema[T]
+	path           string
+	pathNormalized string
+	extract        func(T) V
+}
+
+// Extract binds the path to a function extracting its value from the message
+func Extract[T proto.Message, V any](s *Schema[T], path string, extract func(T) V) *SchemaField[T, V] {
+	return &SchemaField[T, V]{
+		schema:         s,
+		path:           path,
+		pathNormalized: NormalizePath(path),
+		extract:        extract,
+	}
+}
+
+// NonZero assert that the extracted value is not a zero-value
+func (f *SchemaField[T, V]) NonZero() *SchemaField[T, V] {
+	return f.Assert(NonZero, nil)
+}
+
+// NotEqualTo assert that the extracted value is not equal to the supplied target value
+func (f *SchemaField[T, V]) NotEqualTo(notEqualTo V) *SchemaField[T, V] {
+	return f.Assert(NotEqualTo, notEqualTo)
+}
+
+// EqualTo assert that the extracted value is equal to the supplied target value
+func (f *SchemaField[T, V]) EqualTo(equalTo V) *SchemaField[T, V] {
+	return f.Assert(MustEqual, equalTo)
+}
+
+// NonZeroWhenInMask same as NonZero, but only executes if the path is in the field mask
+func (f *SchemaField[T, V]) NonZeroWhenInMask() *SchemaField[T, V] {
+	return f.AssertWhenInMask(NonZero, nil)
+}
+
+// NotEqualToWhenInMask same as NotEqualTo, but only executes if the path is in the field mask
+func (f *SchemaField[T, V]) NotEqualToWhenInMask(notEqualTo V) *SchemaField[T, V] {
+	return f.AssertWhenInMask(NotEqualTo, notEqualTo)
+}
+
+// EqualToWhenInMask same as EqualTo, but only executes if the path is in the field mask
+func (f *SchemaField[T, V]) EqualToWhenInMask(equalTo V) *SchemaField[T, V] {
+	return f.AssertWhenInMask(MustEqual, equalTo)
+}
+
+// Assert assert the policy on the extracted value, with the supplied value to compare to as used by the
+// equivalent DefaultMessageValidator assertion, e.g. Assert(MinLength, 2) or Assert(Between, Bounds{Min: 1, Max: 10})
+func (f *SchemaField[T, V]) Assert(policy Policy, cmpTo any) *SchemaField[T, V] {
+	return f.add(policy, Always, cmpTo)
+}
+
+// AssertWhenInMask same as Assert, but only executes if the path is in the field mask
+func (f *SchemaField[T, V]) AssertWhenInMask(policy Policy, cmpTo any) *SchemaField[T, V] {
+	return f.add(policy, InMask, cmpTo)
+}
+
+func (f *SchemaField[T, V]) add(policy Policy, condition Condition, cmpTo any) *SchemaField[T, V] {
+	f.schema.rules = append(f.schema.rules, &extractedRule[T, V]{
+		field:     f,
+		policy:    policy,
+		condition: condition,
+		cmpTo:     cmpTo,
+	})
+	return f
+}
+
+// extractedRule a rule on the value extracted by a schema field
+type extractedRule[T proto.Message, V any] struct {
+	field     *SchemaField[T, V]
+	policy    Policy
+	condition Condition
+	cmpTo     any
+}
+
+func (r *extractedRule[T, V]) validate(message T, paths map[string]struct{}, errs *ValidationErrors) {
+	// skip extracting the value of rules that do not apply
+	inMask := r.condition == InMask
+	if inMask && !IsPathInMask(r.field.pathNormalized, paths) {
+		return
+	}
+	value := r.field.extract(message)
+	field := Field{
+		path:           r.field.path,
+		pathNormalized: r.field.pathNormalized,
+		value:          value,
+		inMask:         inMask,
+		zero:           isZero(value),
+		policy:         r.policy,
+		condition:      r.condition,
+		cmpTo:          r.cmpTo,
+	}
+	if err := field.Validate(); err != nil {
+		errs.addFieldErr(&field, err)
+	}
+}
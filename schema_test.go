@@ -0,0 +1,167 @@
+package resdes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	v1 "github.com/signal426/resdes/test_protos/gen/test_protos/resdes/v1"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+func userSchema() *Schema[*v1.UpdateUserRequest] {
+	s := NewSchema[*v1.UpdateUserRequest]()
+	Extract(s, "user.id", func(r *v1.UpdateUserRequest) string { return r.GetUser().GetId() }).
+		NonZero()
+	Extract(s, "user.first_name", func(r *v1.UpdateUserRequest) string { return r.GetUser().GetFirstName() }).
+		NonZeroWhenInMask().
+		NotEqualToWhenInMask("bob")
+	Extract(s, "user.last_name", func(r *v1.UpdateUserRequest) string { return r.GetUser().GetLastName() }).
+		AssertWhenInMask(MinLength, 2)
+	Extract(s, "user.primary_address.line1", func(r *v1.UpdateUserRequest) string { return r.GetUser().GetPrimaryAddress().GetLine1() }).
+		NonZeroWhenInMask()
+	return s
+}
+
+func TestSchemaValidations(t *testing.T) {
+	t.Run("it should validate extracted values against the mask supplied on exec", func(t *testing.T) {
+		// arrange
+		schema := userSchema()
+		req := &v1.UpdateUserRequest{
+			User: &v1.User{
+				FirstName: "bob",
+				LastName:  "B",
+			},
+		}
+		expected := &ValidationErrors{
+			FieldErrors: []*FieldError{
+				{
+					Path:   "user.id",
+					Policy: NonZero,
+					Err:    newFieldMustNotBeZeroFailedErr("user.id", ""),
+				},
+				{
+					Path:   "user.first_name",
+					Policy: NotEqualTo,
+					Err:    newFieldMustNotEqualFailedErr("user.first_name", "bob"),
+				},
+			},
+		}
+
+		// act
+		err := schema.Exec(context.Background(), req, "user.firstName")
+
+		// assert
+		assert.Equal(t, expected.Error(), err.Error())
+	})
+
+	t.Run("it should not carry the mask across executions", func(t *testing.T) {
+		// arrange
+		schema := userSchema()
+		req := &v1.UpdateUserRequest{User: &v1.User{Id: "abc123"}}
+
+		// act
+		masked := schema.Exec(context.Background(), req, "user")
+		unmasked := schema.Exec(context.Background(), req)
+
+		// assert
+		assert.Equal(t, []string{"user.first_name", "user.last_name", "user.primary_address.line1"}, masked.Paths())
+		assert.Nil(t, unmasked)
+	})
+
+	t.Run("it should run as the validate stage of an arrangement", func(t *testing.T) {
+		// arrange
+		schema := userSchema().NamedCustomValidation("reserved-ids", func(ctx context.Context, r *v1.UpdateUserRequest, ve *ValidationErrors) error {
+			if r.GetUser().GetId() == "abc123" {
+				ve.AddFieldErr("user.id", errors.New("user id is reserved"))
+			}
+			return nil
+		})
+		req := &v1.UpdateUserRequest{
+			User:       &v1.User{Id: "abc123"},
+			UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"user.first_name"}},
+		}
+
+		// act
+		_, err := Arrange[*v1.UpdateUserRequest, *v1.UpdateUserResponse]().
+			WithValidate(schema.Validator(func(r *v1.UpdateUserRequest) []string {
+				return r.GetUpdateMask().GetPaths()
+			})).
+			Exec(context.Background(), req)
+
+		// assert
+		assert.Equal(t, []string{"user.id", "user.first_name"}, err.GetValidationErrors().Paths())
+		assert.Equal(t, "reserved-ids", err.GetValidationErrors().FieldErrors[0].Validator)
+	})
+
+	t.Run("it should be safe for concurrent use", func(t *testing.T) {
+		// arrange
+		schema := userSchema()
+		var wg sync.WaitGroup
+		results := make([]*ValidationErrors, 50)
+
+		// act
+		for i := range results {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				req := &v1.UpdateUserRequest{User: &v1.User{Id: fmt.Sprint(i)}}
+				if i%2 == 0 {
+					results[i] = schema.Exec(context.Background(), req, "user.first_name")
+				} else {
+					results[i] = schema.Exec(context.Background(), req)
+				}
+			}()
+		}
+		wg.Wait()
+
+		// assert
+		for i, err := range results {
+			if i%2 == 0 {
+				assert.Equal(t, []string{"user.first_name"}, err.Paths())
+			} else {
+				assert.Nil(t, err)
+			}
+		}
+	})
+}
+
+func benchmarkRequest() *v1.UpdateUserRequest {
+	return &v1.UpdateUserRequest{
+		User: &v1.User{
+			Id:             "abc123",
+			FirstName:      "alice",
+			LastName:       "Aliceson",
+			PrimaryAddress: &v1.Address{Line1: "1 Main St"},
+		},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"user.first_name", "user.last_name", "user.primary_address"}},
+	}
+}
+
+func BenchmarkForMessage(b *testing.B) {
+	req := benchmarkRequest()
+	ctx := context.Background()
+	b.ReportAllocs()
+	for b.Loop() {
+		ForMessage[*v1.UpdateUserRequest](req.GetUpdateMask().GetPaths()...).
+			AssertNonZero("user.id", req.GetUser().GetId()).
+			AssertNonZeroWhenInMask("user.first_name", req.GetUser().GetFirstName()).
+			AssertNotEqualToWhenInMask("user.first_name", req.GetUser().GetFirstName(), "bob").
+			AssertMinLengthWhenInMask("user.last_name", req.GetUser().GetLastName(), 2).
+			AssertNonZeroWhenInMask("user.primary_address.line1", req.GetUser().GetPrimaryAddress().GetLine1()).
+			Exec(ctx, req)
+	}
+}
+
+func BenchmarkSchema(b *testing.B) {
+	req := benchmarkRequest()
+	ctx := context.Background()
+	schema := userSchema()
+	b.ReportAllocs()
+	for b.Loop() {
+		schema.Exec(ctx, req, req.GetUpdateMask().GetPaths()...)
+	}
+}
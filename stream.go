@@ -0,0 +1,160 @@
+package resdes
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// StreamAuther a function to run once when a stream is opened, before any message is received
+type StreamAuther func(context.Context) error
+
+// Stream is the transport of a streaming request. Client-streaming requests only
+// receive, server-streaming requests receive once and bidi-streaming requests do both
+type Stream[T proto.Message, U any] interface {
+	Context() context.Context
+	Recv() (T, error)
+	Send(U) error
+}
+
+// StreamServer a function to serve a stream once it has been opened. Every message
+// received from the stream has passed the Auth and Validate stages
+type StreamServer[T proto.Message, U any] func(context.Context, Stream[T, U]) error
+
+// InvalidMessageHandler a function called with a received message that failed validation.
+// Returning an error closes the stream with that error
+type InvalidMessageHandler[T proto.Message] func(context.Context, T, *Error) error
+
+// StreamArrangement represents different actions to take during the
+// execution of serving some streaming request
+type StreamArrangement[T proto.Message, U any] struct {
+	// action to run once when the stream is opened
+	OpenAuth StreamAuther
+
+	// action to run on every received message before running field validations
+	Auth Auther[T]
+
+	// validator to validate every received message
+	Validate MessageValidator[T]
+
+	// action to run when a received message fails validation. The message is skipped
+	OnInvalid InvalidMessageHandler[T]
+
+	// close the stream on the first message that fails validation instead of skipping it
+	FailOnInvalid bool
+
+	// logic to run once the stream has been opened
+	Serve StreamServer[T, U]
+}
+
+// Instantiate a new StreamArrangement to build
+func ArrangeStream[T proto.Message, U any]() *StreamArrangement[T, U] {
+	return &StreamArrangement[T, U]{}
+}
+
+// Add an OpenAuth behavior
+func (r *StreamArrangement[T, U]) WithOpenAuth(act StreamAuther) *StreamArrangement[T, U] {
+	r.OpenAuth = act
+	return r
+}
+
+// Add a per-message Auth behavior
+func (r *StreamArrangement[T, U]) WithAuth(act Auther[T]) *StreamArrangement[T, U] {
+	r.Auth = act
+	return r
+}
+
+// Add a per-message Validate behavior
+func (r *StreamArrangement[T, U]) WithValidate(fv MessageValidator[T]) *StreamArrangement[T, U] {
+	r.Validate = fv
+	return r
+}
+
+// Add an OnInvalid behavior
+func (r *StreamArrangement[T, U]) WithOnInvalid(act InvalidMessageHandler[T]) *StreamArrangement[T, U] {
+	r.OnInvalid = act
+	return r
+}
+
+// Close the stream on the first message that fails validation
+func (r *StreamArrangement[T, U]) WithFailOnInvalid() *StreamArrangement[T, U] {
+	r.FailOnInvalid = true
+	return r
+}
+
+// Add a Serve behavior
+func (r *StreamArrangement[T, U]) WithServe(act StreamServer[T, U]) *StreamArrangement[T, U] {
+	r.Serve = act
+	return r
+}
+
+// Exec runs in the following order:
+// 1. OpenAuth
+// 2. Serve, with Auth and Validate run on every message received from the stream
+// A message that fails Auth ends the stream. A message that fails validation is passed
+// to OnInvalid and skipped, unless FailOnInvalid is set
+func (s *StreamArrangement[T, U]) Exec(stream Stream[T, U]) *Error {
+	ctx := stream.Context()
+	if serr := s.open(ctx); serr != nil {
+		return serr
+	}
+
+	if s.Serve != nil {
+		if err := s.Serve(ctx, &guardedStream[T, U]{Stream: stream, arrangement: s}); err != nil {
+			// errors from receiving a message already hold their stage
+			var serr *Error
+			if errors.As(err, &serr) {
+				return serr
+			}
+			serr = &Error{}
+			serr.SetServeError(err)
+			return serr
+		}
+	}
+
+	return nil
+}
+
+func (s *StreamArrangement[T, U]) open(ctx context.Context) *Error {
+	if s.OpenAuth != nil {
+		if err := s.OpenAuth(ctx); err != nil {
+			serr := &Error{}
+			serr.SetAuthError(err)
+			return serr
+		}
+	}
+	return nil
+}
+
+// recv receives messages until one passes the Auth and Validate stages
+func (s *StreamArrangement[T, U]) recv(ctx context.Context, recv func() (T, error)) (T, error) {
+	for {
+		msg, err := recv()
+		if err != nil {
+			return msg, err
+		}
+		serr := guard(ctx, msg, s.Auth, s.Validate)
+		if serr == nil {
+			return msg, nil
+		}
+		if serr.GetAuthError() != nil || s.FailOnInvalid {
+			return msg, serr
+		}
+		if s.OnInvalid != nil {
+			if err := s.OnInvalid(ctx, msg, serr); err != nil {
+				return msg, err
+			}
+		}
+	}
+}
+
+// guardedStream runs the per-message stages of the arrangement on every received message
+type guardedStream[T proto.Message, U any] struct {
+	Stream[T, U]
+	arrangement *StreamArrangement[T, U]
+}
+
+func (g *guardedStream[T, U]) Recv() (T, error) {
+	return g.arrangement.recv(g.Context(), g.Stream.Recv)
+}
@@ -0,0 +1,233 @@
+package resdes
+
+import (
+	"net/mail"
+	"net/netip"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// AssertMinLength assert that the supplied string value has at least min runes
+func (s *DefaultMessageValidator[T]) AssertMinLength(path string, value string, min int) *DefaultMessageValidator[T] {
+	s.rules = append(s.rules, NewField(path, value, MinLength, Always, min, s.paths))
+	return s
+}
+
+// AssertMaxLength assert that the supplied string value has at most max runes
+func (s *DefaultMessageValidator[T]) AssertMaxLength(path string, value string, max int) *DefaultMessageValidator[T] {
+	s.rules = append(s.rules, NewField(path, value, MaxLength, Always, max, s.paths))
+	return s
+}
+
+// AssertMatches assert that the supplied string value matches the pattern
+func (s *DefaultMessageValidator[T]) AssertMatches(path string, value string, pattern *regexp.Regexp) *DefaultMessageValidator[T] {
+	s.rules = append(s.rules, NewField(path, value, Pattern, Always, pattern, s.paths))
+	return s
+}
+
+// AssertHasPrefix assert that the supplied string value starts with the prefix
+func (s *DefaultMessageValidator[T]) AssertHasPrefix(path string, value string, prefix string) *DefaultMessageValidator[T] {
+	s.rules = append(s.rules, NewField(path, value, Prefix, Always, prefix, s.paths))
+	return s
+}
+
+// AssertHasSuffix assert that the supplied string value ends with the suffix
+func (s *DefaultMessageValidator[T]) AssertHasSuffix(path string, value string, suffix string) *DefaultMessageValidator[T] {
+	s.rules = append(s.rules, NewField(path, value, Suffix, Always, suffix, s.paths))
+	return s
+}
+
+// AssertContains assert that the supplied string value contains the substring
+func (s *DefaultMessageValidator[T]) AssertContains(path string, value string, substr string) *DefaultMessageValidator[T] {
+	s.rules = append(s.rules, NewField(path, value, Contains, Always, substr, s.paths))
+	return s
+}
+
+// AssertEmail assert that the supplied string value is an email address without a display name
+func (s *DefaultMessageValidator[T]) AssertEmail(path string, value string) *DefaultMessageValidator[T] {
+	s.rules = append(s.rules, NewField(path, value, Email, Always, nil, s.paths))
+	return s
+}
+
+// AssertHostname assert that the supplied string value is an RFC 1123 hostname
+func (s *DefaultMessageValidator[T]) AssertHostname(path string, value string) *DefaultMessageValidator[T] {
+	s.rules = append(s.rules, NewField(path, value, Hostname, Always, nil, s.paths))
+	return s
+}
+
+// AssertIP assert that the supplied string value is an IPv4 or IPv6 address
+func (s *DefaultMessageValidator[T]) AssertIP(path string, value string) *DefaultMessageValidator[T] {
+	s.rules = append(s.rules, NewField(path, value, IP, Always, nil, s.paths))
+	return s
+}
+
+// AssertURI assert that the supplied string value is an absolute URI
+func (s *DefaultMessageValidator[T]) AssertURI(path string, value string) *DefaultMessageValidator[T] {
+	s.rules = append(s.rules, NewField(path, value, URI, Always, nil, s.paths))
+	return s
+}
+
+// AssertUUID assert that the supplied string value is a UUID in its canonical 8-4-4-4-12 form
+func (s *DefaultMessageValidator[T]) AssertUUID(path string, value string) *DefaultMessageValidator[T] {
+	s.rules = append(s.rules, NewField(path, value, UUID, Always, nil, s.paths))
+	return s
+}
+
+// AssertMinLengthWhenInMask same as AssertMinLength, but only executes if the supplied path is in the field mask
+func (s *DefaultMessageValidator[T]) AssertMinLengthWhenInMask(path string, value string, min int) *DefaultMessageValidator[T] {
+	s.rules = append(s.rules, NewField(path, value, MinLength, InMask, min, s.paths))
+	return s
+}
+
+// AssertMaxLengthWhenInMask same as AssertMaxLength, but only executes if the supplied path is in the field mask
+func (s *DefaultMessageValidator[T]) AssertMaxLengthWhenInMask(path string, value string, max int) *DefaultMessageValidator[T] {
+	s.rules = append(s.rules, NewField(path, value, MaxLength, InMask, max, s.paths))
+	return s
+}
+
+// AssertMatchesWhenInMask same as AssertMatches, but only executes if the supplied path is in the field mask
+func (s *DefaultMessageValidator[T]) AssertMatchesWhenInMask(path string, value string, pattern *regexp.Regexp) *DefaultMessageValidator[T] {
+	s.rules = append(s.rules, NewField(path, value, Pattern, InMask, pattern, s.paths))
+	return s
+}
+
+// AssertHasPrefixWhenInMask same as AssertHasPrefix, but only executes if the supplied path is in the field mask
+func (s *DefaultMessageValidator[T]) AssertHasPrefixWhenInMask(path string, value string, prefix string) *DefaultMessageValidator[T] {
+	s.rules = append(s.rules, NewField(path, value, Prefix, InMask, prefix, s.paths))
+	return s
+}
+
+// AssertHasSuffixWhenInMask same as AssertHasSuffix, but only executes if the supplied path is in the field mask
+func (s *DefaultMessageValidator[T]) AssertHasSuffixWhenInMask(path string, value string, suffix string) *DefaultMessageValidator[T] {
+	s.rules = append(s.rules, NewField(path, value, Suffix, InMask, suffix, s.paths))
+	return s
+}
+
+// AssertContainsWhenInMask same as AssertContains, but only executes if the supplied path is in the field mask
+func (s *DefaultMessageValidator[T]) AssertContainsWhenInMask(path string, value string, substr string) *DefaultMessageValidator[T] {
+	s.rules = append(s.rules, NewField(path, value, Contains, InMask, substr, s.paths))
+	return s
+}
+
+// AssertEmailWhenInMask same as AssertEmail, but only executes if the supplied path is in the field mask
+func (s *DefaultMessageValidator[T]) AssertEmailWhenInMask(path string, value string) *DefaultMessageValidator[T] {
+	s.rules = append(s.rules, NewField(path, value, Email, InMask, nil, s.paths))
+	return s
+}
+
+// AssertHostnameWhenInMask same as AssertHostname, but only executes if the supplied path is in the field mask
+func (s *DefaultMessageValidator[T]) AssertHostnameWhenInMask(path string, value string) *DefaultMessageValidator[T] {
+	s.rules = append(s.rules, NewField(path, value, Hostname, InMask, nil, s.paths))
+	return s
+}
+
+// AssertIPWhenInMask same as AssertIP, but only executes if the supplied path is in the field mask
+func (s *DefaultMessageValidator[T]) AssertIPWhenInMask(path string, value string) *DefaultMessageValidator[T] {
+	s.rules = append(s.rules, NewField(path, value, IP, InMask, nil, s.paths))
+	return s
+}
+
+// AssertURIWhenInMask same as AssertURI, but only executes if the supplied path is in the field mask
+func (s *DefaultMessageValidator[T]) AssertURIWhenInMask(path string, value string) *DefaultMessageValidator[T] {
+	s.rules = append(s.rules, NewField(path, value, URI, InMask, nil, s.paths))
+	return s
+}
+
+// AssertUUIDWhenInMask same as AssertUUID, but only executes if the supplied path is in the field mask
+func (s *DefaultMessageValidator[T]) AssertUUIDWhenInMask(path string, value string) *DefaultMessageValidator[T] {
+	s.rules = append(s.rules, NewField(path, value, UUID, InMask, nil, s.paths))
+	return s
+}
+
+func (f Field) checkString() error {
+	str, ok := f.value.(string)
+	if !ok {
+		return newFieldsNotComparableErr(f.path, reflect.TypeOf(f.value), reflect.TypeOf(""))
+	}
+	switch f.policy {
+	case MinLength, MaxLength:
+		bound, ok := f.cmpTo.(int)
+		if !ok {
+			return newFieldsNotComparableErr(f.path, reflect.TypeOf(f.cmpTo), reflect.TypeOf(0))
+		}
+		length := utf8.RuneCountInString(str)
+		if f.policy == MinLength && length < bound {
+			return newFieldLengthTooShortErr(f.path, f.value, bound)
+		}
+		if f.policy == MaxLength && length > bound {
+			return newFieldLengthTooLongErr(f.path, f.value, bound)
+		}
+	case Pattern:
+		pattern, ok := f.cmpTo.(*regexp.Regexp)
+		if !ok {
+			return newFieldsNotComparableErr(f.path, reflect.TypeOf(f.cmpTo), reflect.TypeOf(pattern))
+		}
+		if !pattern.MatchString(str) {
+			return newFieldStringConstraintErr(f.path, f.value, pattern, ErrFieldPatternMismatch)
+		}
+	case Prefix, Suffix, Contains:
+		target, ok := f.cmpTo.(string)
+		if !ok {
+			return newFieldsNotComparableErr(f.path, reflect.TypeOf(f.cmpTo), reflect.TypeOf(""))
+		}
+		if f.policy == Prefix && !strings.HasPrefix(str, target) {
+			return newFieldStringConstraintErr(f.path, f.value, target, ErrFieldMissingPrefix)
+		}
+		if f.policy == Suffix && !strings.HasSuffix(str, target) {
+			return newFieldStringConstraintErr(f.path, f.value, target, ErrFieldMissingSuffix)
+		}
+		if f.policy == Contains && !strings.Contains(str, target) {
+			return newFieldStringConstraintErr(f.path, f.value, target, ErrFieldMissingSubstring)
+		}
+	case Email:
+		if !isEmail(str) {
+			return newFieldFormatErr(f.path, f.value, ErrFieldInvalidEmail)
+		}
+	case Hostname:
+		if !isHostname(str) {
+			return newFieldFormatErr(f.path, f.value, ErrFieldInvalidHostname)
+		}
+	case IP:
+		if _, err := netip.ParseAddr(str); err != nil {
+			return newFieldFormatErr(f.path, f.value, ErrFieldInvalidIP)
+		}
+	case URI:
+		if u, err := url.Parse(str); err != nil || !u.IsAbs() {
+			return newFieldFormatErr(f.path, f.value, ErrFieldInvalidURI)
+		}
+	case UUID:
+		if !uuidPattern.MatchString(str) {
+			return newFieldFormatErr(f.path, f.value, ErrFieldInvalidUUID)
+		}
+	}
+	return nil
+}
+
+// isEmail reports whether s is a bare email address, e.g. bob@example.com
+func isEmail(s string) bool {
+	addr, err := mail.ParseAddress(s)
+	return err == nil && addr.Name == "" && addr.Address == s
+}
+
+// isHostname reports whether s is a hostname as described in RFC 1123
+func isHostname(s string) bool {
+	if len(s) == 0 || len(s) > 253 {
+		return false
+	}
+	for _, label := range strings.Split(s, ".") {
+		if len(label) == 0 || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return false
+		}
+		for _, c := range label {
+			if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-') {
+				return false
+			}
+		}
+	}
+	return true
+}
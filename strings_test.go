@@ -0,0 +1,164 @@
+package resdes
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	v1 "github.com/signal426/resdes/test_protos/gen/test_protos/resdes/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStringValidations(t *testing.T) {
+	t.Run("it should assert patterns and substrings", func(t *testing.T) {
+		// arrange
+		req := &v1.CreateUserRequest{
+			User: &v1.User{
+				Id:        "usr_123",
+				FirstName: "bob",
+				LastName:  "Bobson",
+			},
+		}
+		idPattern := regexp.MustCompile(`^usr_[a-z]+$`)
+		expected := &ValidationErrors{
+			FieldErrors: []*FieldError{
+				{
+					Path:   "user.id",
+					Policy: Pattern,
+					Err:    newFieldStringConstraintErr("user.id", "usr_123", idPattern, ErrFieldPatternMismatch),
+				},
+				{
+					Path:   "user.first_name",
+					Policy: Prefix,
+					Err:    newFieldStringConstraintErr("user.first_name", "bob", "B", ErrFieldMissingPrefix),
+				},
+				{
+					Path:   "user.last_name",
+					Policy: Contains,
+					Err:    newFieldStringConstraintErr("user.last_name", "Bobson", "bob", ErrFieldMissingSubstring),
+				},
+			},
+		}
+
+		// act
+		err := ForMessage[*v1.CreateUserRequest]().
+			AssertMatches("user.id", req.GetUser().GetId(), idPattern).
+			AssertHasPrefix("user.id", req.GetUser().GetId(), "usr_").
+			AssertHasPrefix("user.first_name", req.GetUser().GetFirstName(), "B").
+			AssertHasSuffix("user.last_name", req.GetUser().GetLastName(), "son").
+			AssertContains("user.last_name", req.GetUser().GetLastName(), "bob").
+			Exec(context.Background(), req)
+
+		// assert
+		assert.Error(t, err)
+		assert.Equal(t, expected.Error(), err.Error())
+	})
+
+	t.Run("it should assert well-known formats", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			assert  func(*DefaultMessageValidator[*v1.User], string) *DefaultMessageValidator[*v1.User]
+			valid   []string
+			invalid []string
+			err     error
+		}{
+			{
+				name: "email",
+				assert: func(v *DefaultMessageValidator[*v1.User], s string) *DefaultMessageValidator[*v1.User] {
+					return v.AssertEmail("id", s)
+				},
+				valid:   []string{"bob@example.com", "bob.bobson+tag@mail.example.co"},
+				invalid: []string{"bob", "Bob <bob@example.com>", "bob@", ""},
+				err:     ErrFieldInvalidEmail,
+			},
+			{
+				name: "hostname",
+				assert: func(v *DefaultMessageValidator[*v1.User], s string) *DefaultMessageValidator[*v1.User] {
+					return v.AssertHostname("id", s)
+				},
+				valid:   []string{"example.com", "api-1.internal", "localhost"},
+				invalid: []string{"-example.com", "example..com", "exa_mple.com", ""},
+				err:     ErrFieldInvalidHostname,
+			},
+			{
+				name: "ip",
+				assert: func(v *DefaultMessageValidator[*v1.User], s string) *DefaultMessageValidator[*v1.User] {
+					return v.AssertIP("id", s)
+				},
+				valid:   []string{"10.0.0.1", "::1", "2001:db8::68"},
+				invalid: []string{"10.0.0", "256.0.0.1", "example.com"},
+				err:     ErrFieldInvalidIP,
+			},
+			{
+				name: "uri",
+				assert: func(v *DefaultMessageValidator[*v1.User], s string) *DefaultMessageValidator[*v1.User] {
+					return v.AssertURI("id", s)
+				},
+				valid:   []string{"https://example.com/users?id=1", "urn:isbn:0451450523"},
+				invalid: []string{"/users/1", "example.com", "http://[::1"},
+				err:     ErrFieldInvalidURI,
+			},
+			{
+				name: "uuid",
+				assert: func(v *DefaultMessageValidator[*v1.User], s string) *DefaultMessageValidator[*v1.User] {
+					return v.AssertUUID("id", s)
+				},
+				valid:   []string{"f47ac10b-58cc-4372-a567-0e02b2c3d479", "F47AC10B-58CC-4372-A567-0E02B2C3D479"},
+				invalid: []string{"f47ac10b58cc4372a5670e02b2c3d479", "f47ac10b-58cc-4372-a567-0e02b2c3d47", "g47ac10b-58cc-4372-a567-0e02b2c3d479"},
+				err:     ErrFieldInvalidUUID,
+			},
+		}
+		for _, tt := range tests {
+			for _, s := range tt.valid {
+				// act
+				err := tt.assert(ForMessage[*v1.User](), s).Exec(context.Background(), &v1.User{})
+
+				// assert
+				assert.Nil(t, err, "%s: %q", tt.name, s)
+			}
+			for _, s := range tt.invalid {
+				// act
+				err := tt.assert(ForMessage[*v1.User](), s).Exec(context.Background(), &v1.User{})
+
+				// assert
+				assert.ErrorIs(t, err, tt.err, "%s: %q", tt.name, s)
+			}
+		}
+	})
+
+	t.Run("it should only assert in mask paths when in mask", func(t *testing.T) {
+		// arrange
+		req := &v1.UpdateUserRequest{
+			User: &v1.User{
+				Id:        "abc",
+				FirstName: "bob",
+			},
+		}
+
+		// act
+		err := ForMessage[*v1.UpdateUserRequest]("user.first_name").
+			AssertUUIDWhenInMask("user.id", req.GetUser().GetId()).
+			AssertHasPrefixWhenInMask("user.first_name", req.GetUser().GetFirstName(), "B").
+			Exec(context.Background(), req)
+
+		// assert
+		assert.Error(t, err)
+		assert.Equal(t, []string{"user.first_name"}, err.Paths())
+	})
+
+	t.Run("it should round-trip string policies through a grpc status", func(t *testing.T) {
+		// arrange
+		req := &v1.User{Id: "abc"}
+		serr := &Error{}
+		serr.SetValidationErrors(ForMessage[*v1.User]().
+			AssertUUID("id", req.GetId()).
+			Exec(context.Background(), req))
+
+		// act
+		err := FromGrpcStatus(serr.ToGrpcStatus())
+
+		// assert
+		assert.Equal(t, UUID, err.GetValidationErrors().AsMap()["id"].Policy)
+		assert.ErrorIs(t, err, ErrFieldInvalidUUID)
+	})
+}
@@ -7,6 +7,7 @@
 package v1
 
 import (
+	_ "github.com/signal426/resdes/proto/gen/resdes/v1"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
@@ -22,6 +23,58 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type UserStatus int32
+
+const (
+	UserStatus_USER_STATUS_UNSPECIFIED UserStatus = 0
+	UserStatus_USER_STATUS_ACTIVE      UserStatus = 1
+	UserStatus_USER_STATUS_SUSPENDED   UserStatus = 2
+	UserStatus_USER_STATUS_DELETED     UserStatus = 3
+)
+
+// Enum value maps for UserStatus.
+var (
+	UserStatus_name = map[int32]string{
+		0: "USER_STATUS_UNSPECIFIED",
+		1: "USER_STATUS_ACTIVE",
+		2: "USER_STATUS_SUSPENDED",
+		3: "USER_STATUS_DELETED",
+	}
+	UserStatus_value = map[string]int32{
+		"USER_STATUS_UNSPECIFIED": 0,
+		"USER_STATUS_ACTIVE":      1,
+		"USER_STATUS_SUSPENDED":   2,
+		"USER_STATUS_DELETED":     3,
+	}
+)
+
+func (x UserStatus) Enum() *UserStatus {
+	p := new(UserStatus)
+	*p = x
+	return p
+}
+
+func (x UserStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (UserStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_resdes_v1_test_proto_enumTypes[0].Descriptor()
+}
+
+func (UserStatus) Type() protoreflect.EnumType {
+	return &file_resdes_v1_test_proto_enumTypes[0]
+}
+
+func (x UserStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use UserStatus.Descriptor instead.
+func (UserStatus) EnumDescriptor() ([]byte, []int) {
+	return file_resdes_v1_test_proto_rawDescGZIP(), []int{0}
+}
+
 type Address struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Line1         string                 `protobuf:"bytes,1,opt,name=line1,proto3" json:"line1,omitempty"`
@@ -81,8 +134,15 @@ type User struct {
 	LastName           string                 `protobuf:"bytes,4,opt,name=last_name,json=lastName,proto3" json:"last_name,omitempty"`
 	PrimaryAddress     *Address               `protobuf:"bytes,5,opt,name=primary_address,json=primaryAddress,proto3" json:"primary_address,omitempty"`
 	SecondaryAddresses []*Address             `protobuf:"bytes,6,rep,name=secondary_addresses,json=secondaryAddresses,proto3" json:"secondary_addresses,omitempty"`
-	unknownFields      protoimpl.UnknownFields
-	sizeCache          protoimpl.SizeCache
+	Labels             map[string]string      `protobuf:"bytes,7,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Status             UserStatus             `protobuf:"varint,8,opt,name=status,proto3,enum=resdes.v1.UserStatus" json:"status,omitempty"`
+	// Types that are valid to be assigned to Contact:
+	//
+	//	*User_Email
+	//	*User_Phone
+	Contact       isUser_Contact `protobuf_oneof:"contact"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *User) Reset() {
@@ -150,6 +210,61 @@ func (x *User) GetSecondaryAddresses() []*Address {
 	return nil
 }
 
+func (x *User) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *User) GetStatus() UserStatus {
+	if x != nil {
+		return x.Status
+	}
+	return UserStatus_USER_STATUS_UNSPECIFIED
+}
+
+func (x *User) GetContact() isUser_Contact {
+	if x != nil {
+		return x.Contact
+	}
+	return nil
+}
+
+func (x *User) GetEmail() string {
+	if x != nil {
+		if x, ok := x.Contact.(*User_Email); ok {
+			return x.Email
+		}
+	}
+	return ""
+}
+
+func (x *User) GetPhone() string {
+	if x != nil {
+		if x, ok := x.Contact.(*User_Phone); ok {
+			return x.Phone
+		}
+	}
+	return ""
+}
+
+type isUser_Contact interface {
+	isUser_Contact()
+}
+
+type User_Email struct {
+	Email string `protobuf:"bytes,9,opt,name=email,proto3,oneof"`
+}
+
+type User_Phone struct {
+	Phone string `protobuf:"bytes,10,opt,name=phone,proto3,oneof"`
+}
+
+func (*User_Email) isUser_Contact() {}
+
+func (*User_Phone) isUser_Contact() {}
+
 type CreateUserRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	User          *User                  `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
@@ -334,31 +449,167 @@ func (x *UpdateUserResponse) GetUser() *User {
 	return nil
 }
 
+type GetUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ReadMask      *fieldmaskpb.FieldMask `protobuf:"bytes,2,opt,name=read_mask,json=readMask,proto3" json:"read_mask,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUserRequest) Reset() {
+	*x = GetUserRequest{}
+	mi := &file_resdes_v1_test_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUserRequest) ProtoMessage() {}
+
+func (x *GetUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_resdes_v1_test_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUserRequest.ProtoReflect.Descriptor instead.
+func (*GetUserRequest) Descriptor() ([]byte, []int) {
+	return file_resdes_v1_test_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetUserRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *GetUserRequest) GetReadMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.ReadMask
+	}
+	return nil
+}
+
+type PatchUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	User          *User                  `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	ReadMask      *fieldmaskpb.FieldMask `protobuf:"bytes,2,opt,name=read_mask,json=readMask,proto3" json:"read_mask,omitempty"`
+	UpdateMask    *fieldmaskpb.FieldMask `protobuf:"bytes,3,opt,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PatchUserRequest) Reset() {
+	*x = PatchUserRequest{}
+	mi := &file_resdes_v1_test_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PatchUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PatchUserRequest) ProtoMessage() {}
+
+func (x *PatchUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_resdes_v1_test_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PatchUserRequest.ProtoReflect.Descriptor instead.
+func (*PatchUserRequest) Descriptor() ([]byte, []int) {
+	return file_resdes_v1_test_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *PatchUserRequest) GetUser() *User {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+func (x *PatchUserRequest) GetReadMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.ReadMask
+	}
+	return nil
+}
+
+func (x *PatchUserRequest) GetUpdateMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.UpdateMask
+	}
+	return nil
+}
+
 var File_resdes_v1_test_proto protoreflect.FileDescriptor
 
 const file_resdes_v1_test_proto_rawDesc = "" +
 	"\n" +
-	"\x14resdes/v1/test.proto\x12\tresdes.v1\x1a google/protobuf/field_mask.proto\"5\n" +
-	"\aAddress\x12\x14\n" +
-	"\x05line1\x18\x01 \x01(\tR\x05line1\x12\x14\n" +
-	"\x05line2\x18\x02 \x01(\tR\x05line2\"\xd4\x01\n" +
-	"\x04User\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
+	"\x14resdes/v1/test.proto\x12\tresdes.v1\x1a google/protobuf/field_mask.proto\x1a\x17resdes/v1/options.proto\"E\n" +
+	"\aAddress\x12\x1c\n" +
+	"\x05line1\x18\x01 \x01(\tB\x06\xd2\xcf\x18\x02\b\x01R\x05line1\x12\x1c\n" +
+	"\x05line2\x18\x02 \x01(\tB\x06\xd2\xcf\x18\x020dR\x05line2\"\xd1\x03\n" +
+	"\x04User\x12\x16\n" +
+	"\x02id\x18\x01 \x01(\tB\x06\xd2\xcf\x18\x02\b\x01R\x02id\x12,\n" +
 	"\n" +
-	"first_name\x18\x02 \x01(\tR\tfirstName\x12\x1b\n" +
-	"\tlast_name\x18\x04 \x01(\tR\blastName\x12;\n" +
+	"first_name\x18\x02 \x01(\tB\r\xd2\xcf\x18\t\b\x01\x10\x01\x1a\x03bobR\tfirstName\x12'\n" +
+	"\tlast_name\x18\x04 \x01(\tB\n" +
+	"\xd2\xcf\x18\x06\x10\x01(\x0202R\blastName\x12;\n" +
 	"\x0fprimary_address\x18\x05 \x01(\v2\x12.resdes.v1.AddressR\x0eprimaryAddress\x12C\n" +
-	"\x13secondary_addresses\x18\x06 \x03(\v2\x12.resdes.v1.AddressR\x12secondaryAddresses\"8\n" +
-	"\x11CreateUserRequest\x12#\n" +
-	"\x04user\x18\x01 \x01(\v2\x0f.resdes.v1.UserR\x04user\"9\n" +
+	"\x13secondary_addresses\x18\x06 \x03(\v2\x12.resdes.v1.AddressR\x12secondaryAddresses\x123\n" +
+	"\x06labels\x18\a \x03(\v2\x1b.resdes.v1.User.LabelsEntryR\x06labels\x12-\n" +
+	"\x06status\x18\b \x01(\x0e2\x15.resdes.v1.UserStatusR\x06status\x12\x16\n" +
+	"\x05email\x18\t \x01(\tH\x00R\x05email\x12\x16\n" +
+	"\x05phone\x18\n" +
+	" \x01(\tH\x00R\x05phone\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01B\t\n" +
+	"\acontact\"@\n" +
+	"\x11CreateUserRequest\x12+\n" +
+	"\x04user\x18\x01 \x01(\v2\x0f.resdes.v1.UserB\x06\xd2\xcf\x18\x02\b\x01R\x04user\"9\n" +
 	"\x12CreateUserResponse\x12#\n" +
-	"\x04user\x18\x01 \x01(\v2\x0f.resdes.v1.UserR\x04user\"u\n" +
-	"\x11UpdateUserRequest\x12#\n" +
-	"\x04user\x18\x01 \x01(\v2\x0f.resdes.v1.UserR\x04user\x12;\n" +
+	"\x04user\x18\x01 \x01(\v2\x0f.resdes.v1.UserR\x04user\"}\n" +
+	"\x11UpdateUserRequest\x12+\n" +
+	"\x04user\x18\x01 \x01(\v2\x0f.resdes.v1.UserB\x06\xd2\xcf\x18\x02\b\x01R\x04user\x12;\n" +
 	"\vupdate_mask\x18\x02 \x01(\v2\x1a.google.protobuf.FieldMaskR\n" +
 	"updateMask\"9\n" +
 	"\x12UpdateUserResponse\x12#\n" +
-	"\x04user\x18\x01 \x01(\v2\x0f.resdes.v1.UserR\x04userB\x1aZ\x18test_protos/resdes/v1;v1b\x06proto3"
+	"\x04user\x18\x01 \x01(\v2\x0f.resdes.v1.UserR\x04user\"a\n" +
+	"\x0eGetUserRequest\x12\x16\n" +
+	"\x02id\x18\x01 \x01(\tB\x06\xd2\xcf\x18\x02\b\x01R\x02id\x127\n" +
+	"\tread_mask\x18\x02 \x01(\v2\x1a.google.protobuf.FieldMaskR\breadMask\"\xad\x01\n" +
+	"\x10PatchUserRequest\x12#\n" +
+	"\x04user\x18\x01 \x01(\v2\x0f.resdes.v1.UserR\x04user\x127\n" +
+	"\tread_mask\x18\x02 \x01(\v2\x1a.google.protobuf.FieldMaskR\breadMask\x12;\n" +
+	"\vupdate_mask\x18\x03 \x01(\v2\x1a.google.protobuf.FieldMaskR\n" +
+	"updateMask*u\n" +
+	"\n" +
+	"UserStatus\x12\x1b\n" +
+	"\x17USER_STATUS_UNSPECIFIED\x10\x00\x12\x16\n" +
+	"\x12USER_STATUS_ACTIVE\x10\x01\x12\x19\n" +
+	"\x15USER_STATUS_SUSPENDED\x10\x02\x12\x17\n" +
+	"\x13USER_STATUS_DELETED\x10\x03BFZDgithub.com/signal426/resdes/test_protos/gen/test_protos/resdes/v1;v1b\x06proto3"
 
 var (
 	file_resdes_v1_test_proto_rawDescOnce sync.Once
@@ -372,29 +623,40 @@ func file_resdes_v1_test_proto_rawDescGZIP() []byte {
 	return file_resdes_v1_test_proto_rawDescData
 }
 
-var file_resdes_v1_test_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_resdes_v1_test_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_resdes_v1_test_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
 var file_resdes_v1_test_proto_goTypes = []any{
-	(*Address)(nil),               // 0: resdes.v1.Address
-	(*User)(nil),                  // 1: resdes.v1.User
-	(*CreateUserRequest)(nil),     // 2: resdes.v1.CreateUserRequest
-	(*CreateUserResponse)(nil),    // 3: resdes.v1.CreateUserResponse
-	(*UpdateUserRequest)(nil),     // 4: resdes.v1.UpdateUserRequest
-	(*UpdateUserResponse)(nil),    // 5: resdes.v1.UpdateUserResponse
-	(*fieldmaskpb.FieldMask)(nil), // 6: google.protobuf.FieldMask
+	(UserStatus)(0),               // 0: resdes.v1.UserStatus
+	(*Address)(nil),               // 1: resdes.v1.Address
+	(*User)(nil),                  // 2: resdes.v1.User
+	(*CreateUserRequest)(nil),     // 3: resdes.v1.CreateUserRequest
+	(*CreateUserResponse)(nil),    // 4: resdes.v1.CreateUserResponse
+	(*UpdateUserRequest)(nil),     // 5: resdes.v1.UpdateUserRequest
+	(*UpdateUserResponse)(nil),    // 6: resdes.v1.UpdateUserResponse
+	(*GetUserRequest)(nil),        // 7: resdes.v1.GetUserRequest
+	(*PatchUserRequest)(nil),      // 8: resdes.v1.PatchUserRequest
+	nil,                           // 9: resdes.v1.User.LabelsEntry
+	(*fieldmaskpb.FieldMask)(nil), // 10: google.protobuf.FieldMask
 }
 var file_resdes_v1_test_proto_depIdxs = []int32{
-	0, // 0: resdes.v1.User.primary_address:type_name -> resdes.v1.Address
-	0, // 1: resdes.v1.User.secondary_addresses:type_name -> resdes.v1.Address
-	1, // 2: resdes.v1.CreateUserRequest.user:type_name -> resdes.v1.User
-	1, // 3: resdes.v1.CreateUserResponse.user:type_name -> resdes.v1.User
-	1, // 4: resdes.v1.UpdateUserRequest.user:type_name -> resdes.v1.User
-	6, // 5: resdes.v1.UpdateUserRequest.update_mask:type_name -> google.protobuf.FieldMask
-	1, // 6: resdes.v1.UpdateUserResponse.user:type_name -> resdes.v1.User
-	7, // [7:7] is the sub-list for method output_type
-	7, // [7:7] is the sub-list for method input_type
-	7, // [7:7] is the sub-list for extension type_name
-	7, // [7:7] is the sub-list for extension extendee
-	0, // [0:7] is the sub-list for field type_name
+	1,  // 0: resdes.v1.User.primary_address:type_name -> resdes.v1.Address
+	1,  // 1: resdes.v1.User.secondary_addresses:type_name -> resdes.v1.Address
+	9,  // 2: resdes.v1.User.labels:type_name -> resdes.v1.User.LabelsEntry
+	0,  // 3: resdes.v1.User.status:type_name -> resdes.v1.UserStatus
+	2,  // 4: resdes.v1.CreateUserRequest.user:type_name -> resdes.v1.User
+	2,  // 5: resdes.v1.CreateUserResponse.user:type_name -> resdes.v1.User
+	2,  // 6: resdes.v1.UpdateUserRequest.user:type_name -> resdes.v1.User
+	10, // 7: resdes.v1.UpdateUserRequest.update_mask:type_name -> google.protobuf.FieldMask
+	2,  // 8: resdes.v1.UpdateUserResponse.user:type_name -> resdes.v1.User
+	10, // 9: resdes.v1.GetUserRequest.read_mask:type_name -> google.protobuf.FieldMask
+	2,  // 10: resdes.v1.PatchUserRequest.user:type_name -> resdes.v1.User
+	10, // 11: resdes.v1.PatchUserRequest.read_mask:type_name -> google.protobuf.FieldMask
+	10, // 12: resdes.v1.PatchUserRequest.update_mask:type_name -> google.protobuf.FieldMask
+	13, // [13:13] is the sub-list for method output_type
+	13, // [13:13] is the sub-list for method input_type
+	13, // [13:13] is the sub-list for extension type_name
+	13, // [13:13] is the sub-list for extension extendee
+	0,  // [0:13] is the sub-list for field type_name
 }
 
 func init() { file_resdes_v1_test_proto_init() }
@@ -402,18 +664,23 @@ func file_resdes_v1_test_proto_init() {
 	if File_resdes_v1_test_proto != nil {
 		return
 	}
+	file_resdes_v1_test_proto_msgTypes[1].OneofWrappers = []any{
+		(*User_Email)(nil),
+		(*User_Phone)(nil),
+	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_resdes_v1_test_proto_rawDesc), len(file_resdes_v1_test_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   6,
+			NumEnums:      1,
+			NumMessages:   9,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
 		GoTypes:           file_resdes_v1_test_proto_goTypes,
 		DependencyIndexes: file_resdes_v1_test_proto_depIdxs,
+		EnumInfos:         file_resdes_v1_test_proto_enumTypes,
 		MessageInfos:      file_resdes_v1_test_proto_msgTypes,
 	}.Build()
 	File_resdes_v1_test_proto = out.File
@@ -0,0 +1,150 @@
+// Code generated by protoc-gen-resdes. DO NOT EDIT.
+// source: resdes/v1/test.proto
+
+package v1resdes
+
+import (
+	resdes "github.com/signal426/resdes"
+	v1 "github.com/signal426/resdes/test_protos/gen/test_protos/resdes/v1"
+)
+
+// NewAddressValidator returns a validator for the rules declared on resdes.v1.Address
+func NewAddressValidator(msg *v1.Address) *resdes.DefaultMessageValidator[*v1.Address] {
+	v := resdes.ForMessage[*v1.Address]()
+	v.AssertNonZero("line1", msg.GetLine1())
+	v.AssertMaxLength("line2", msg.GetLine2(), 100)
+	return v
+}
+
+// NewUserValidator returns a validator for the rules declared on resdes.v1.User
+func NewUserValidator(msg *v1.User) *resdes.DefaultMessageValidator[*v1.User] {
+	v := resdes.ForMessage[*v1.User]()
+	v.AssertNonZero("id", msg.GetId())
+	v.AssertNonZeroWhenInMask("first_name", msg.GetFirstName())
+	v.AssertNotEqualToWhenInMask("first_name", msg.GetFirstName(), "bob")
+	v.AssertMinLengthWhenInMask("last_name", msg.GetLastName(), 2)
+	v.AssertMaxLengthWhenInMask("last_name", msg.GetLastName(), 50)
+	if msg.GetPrimaryAddress() != nil {
+		v.AssertNonZero("primary_address.line1", msg.GetPrimaryAddress().GetLine1())
+		v.AssertMaxLength("primary_address.line2", msg.GetPrimaryAddress().GetLine2(), 100)
+	}
+	v.ForEach("secondary_addresses", func(each *resdes.EachRules) {
+		each.Require("line1")
+		each.RequireMaxLength("line2", 100)
+	})
+	return v
+}
+
+// NewCreateUserRequestValidator returns a validator for the rules declared on resdes.v1.CreateUserRequest
+func NewCreateUserRequestValidator(msg *v1.CreateUserRequest) *resdes.DefaultMessageValidator[*v1.CreateUserRequest] {
+	v := resdes.ForMessage[*v1.CreateUserRequest]()
+	v.AssertNonZero("user", msg.GetUser())
+	if msg.GetUser() != nil {
+		v.AssertNonZero("user.id", msg.GetUser().GetId())
+		v.AssertNonZeroWhenInMask("user.first_name", msg.GetUser().GetFirstName())
+		v.AssertNotEqualToWhenInMask("user.first_name", msg.GetUser().GetFirstName(), "bob")
+		v.AssertMinLengthWhenInMask("user.last_name", msg.GetUser().GetLastName(), 2)
+		v.AssertMaxLengthWhenInMask("user.last_name", msg.GetUser().GetLastName(), 50)
+		if msg.GetUser().GetPrimaryAddress() != nil {
+			v.AssertNonZero("user.primary_address.line1", msg.GetUser().GetPrimaryAddress().GetLine1())
+			v.AssertMaxLength("user.primary_address.line2", msg.GetUser().GetPrimaryAddress().GetLine2(), 100)
+		}
+		v.ForEach("user.secondary_addresses", func(each *resdes.EachRules) {
+			each.Require("line1")
+			each.RequireMaxLength("line2", 100)
+		})
+	}
+	return v
+}
+
+// NewCreateUserResponseValidator returns a validator for the rules declared on resdes.v1.CreateUserResponse
+func NewCreateUserResponseValidator(msg *v1.CreateUserResponse) *resdes.DefaultMessageValidator[*v1.CreateUserResponse] {
+	v := resdes.ForMessage[*v1.CreateUserResponse]()
+	if msg.GetUser() != nil {
+		v.AssertNonZero("user.id", msg.GetUser().GetId())
+		v.AssertNonZeroWhenInMask("user.first_name", msg.GetUser().GetFirstName())
+		v.AssertNotEqualToWhenInMask("user.first_name", msg.GetUser().GetFirstName(), "bob")
+		v.AssertMinLengthWhenInMask("user.last_name", msg.GetUser().GetLastName(), 2)
+		v.AssertMaxLengthWhenInMask("user.last_name", msg.GetUser().GetLastName(), 50)
+		if msg.GetUser().GetPrimaryAddress() != nil {
+			v.AssertNonZero("user.primary_address.line1", msg.GetUser().GetPrimaryAddress().GetLine1())
+			v.AssertMaxLength("user.primary_address.line2", msg.GetUser().GetPrimaryAddress().GetLine2(), 100)
+		}
+		v.ForEach("user.secondary_addresses", func(each *resdes.EachRules) {
+			each.Require("line1")
+			each.RequireMaxLength("line2", 100)
+		})
+	}
+	return v
+}
+
+// NewUpdateUserRequestValidator returns a validator for the rules declared on resdes.v1.UpdateUserRequest
+func NewUpdateUserRequestValidator(msg *v1.UpdateUserRequest) *resdes.DefaultMessageValidator[*v1.UpdateUserRequest] {
+	v := resdes.ForMessage[*v1.UpdateUserRequest](msg.GetUpdateMask().GetPaths()...)
+	v.AssertNonZero("user", msg.GetUser())
+	if msg.GetUser() != nil {
+		v.AssertNonZero("user.id", msg.GetUser().GetId())
+		v.AssertNonZeroWhenInMask("user.first_name", msg.GetUser().GetFirstName())
+		v.AssertNotEqualToWhenInMask("user.first_name", msg.GetUser().GetFirstName(), "bob")
+		v.AssertMinLengthWhenInMask("user.last_name", msg.GetUser().GetLastName(), 2)
+		v.AssertMaxLengthWhenInMask("user.last_name", msg.GetUser().GetLastName(), 50)
+		if msg.GetUser().GetPrimaryAddress() != nil {
+			v.AssertNonZero("user.primary_address.line1", msg.GetUser().GetPrimaryAddress().GetLine1())
+			v.AssertMaxLength("user.primary_address.line2", msg.GetUser().GetPrimaryAddress().GetLine2(), 100)
+		}
+		v.ForEach("user.secondary_addresses", func(each *resdes.EachRules) {
+			each.Require("line1")
+			each.RequireMaxLength("line2", 100)
+		})
+	}
+	return v
+}
+
+// NewUpdateUserResponseValidator returns a validator for the rules declared on resdes.v1.UpdateUserResponse
+func NewUpdateUserResponseValidator(msg *v1.UpdateUserResponse) *resdes.DefaultMessageValidator[*v1.UpdateUserResponse] {
+	v := resdes.ForMessage[*v1.UpdateUserResponse]()
+	if msg.GetUser() != nil {
+		v.AssertNonZero("user.id", msg.GetUser().GetId())
+		v.AssertNonZeroWhenInMask("user.first_name", msg.GetUser().GetFirstName())
+		v.AssertNotEqualToWhenInMask("user.first_name", msg.GetUser().GetFirstName(), "bob")
+		v.AssertMinLengthWhenInMask("user.last_name", msg.GetUser().GetLastName(), 2)
+		v.AssertMaxLengthWhenInMask("user.last_name", msg.GetUser().GetLastName(), 50)
+		if msg.GetUser().GetPrimaryAddress() != nil {
+			v.AssertNonZero("user.primary_address.line1", msg.GetUser().GetPrimaryAddress().GetLine1())
+			v.AssertMaxLength("user.primary_address.line2", msg.GetUser().GetPrimaryAddress().GetLine2(), 100)
+		}
+		v.ForEach("user.secondary_addresses", func(each *resdes.EachRules) {
+			each.Require("line1")
+			each.RequireMaxLength("line2", 100)
+		})
+	}
+	return v
+}
+
+// NewGetUserRequestValidator returns a validator for the rules declared on resdes.v1.GetUserRequest
+func NewGetUserRequestValidator(msg *v1.GetUserRequest) *resdes.DefaultMessageValidator[*v1.GetUserRequest] {
+	v := resdes.ForMessage[*v1.GetUserRequest]()
+	v.AssertNonZero("id", msg.GetId())
+	return v
+}
+
+// NewPatchUserRequestValidator returns a validator for the rules declared on resdes.v1.PatchUserRequest
+func NewPatchUserRequestValidator(msg *v1.PatchUserRequest) *resdes.DefaultMessageValidator[*v1.PatchUserRequest] {
+	v := resdes.ForMessage[*v1.PatchUserRequest](msg.GetUpdateMask().GetPaths()...)
+	if msg.GetUser() != nil {
+		v.AssertNonZero("user.id", msg.GetUser().GetId())
+		v.AssertNonZeroWhenInMask("user.first_name", msg.GetUser().GetFirstName())
+		v.AssertNotEqualToWhenInMask("user.first_name", msg.GetUser().GetFirstName(), "bob")
+		v.AssertMinLengthWhenInMask("user.last_name", msg.GetUser().GetLastName(), 2)
+		v.AssertMaxLengthWhenInMask("user.last_name", msg.GetUser().GetLastName(), 50)
+		if msg.GetUser().GetPrimaryAddress() != nil {
+			v.AssertNonZero("user.primary_address.line1", msg.GetUser().GetPrimaryAddress().GetLine1())
+			v.AssertMaxLength("user.primary_address.line2", msg.GetUser().GetPrimaryAddress().GetLine2(), 100)
+		}
+		v.ForEach("user.secondary_addresses", func(each *resdes.EachRules) {
+			each.Require("line1")
+			each.RequireMaxLength("line2", 100)
+		})
+	}
+	return v
+}
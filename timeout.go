@@ -0,0 +1,66 @@
+package resdes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// WithStageTimeout runs the Auth, Validate or Serve stage, and its middleware, with a context
+// that is done after the timeout. A stage that fails once its timeout has passed returns a
+// TimeoutError. Stages are not abandoned, so they must return when their context is done
+func (r *Arrangement[T, U]) WithStageTimeout(stage Stage, timeout time.Duration) *Arrangement[T, U] {
+	if stage == NoStage {
+		panic(fmt.Sprintf("resdes: no timeout can be set on the %s stage", stage))
+	}
+	if r.timeouts == nil {
+		r.timeouts = make(map[Stage]time.Duration)
+	}
+	r.timeouts[stage] = timeout
+	return r
+}
+
+// WithMinDeadline rejects a request with a TimeoutError before the Serve stage if its context
+// has a deadline with less than the supplied time left
+func (r *Arrangement[T, U]) WithMinDeadline(remaining time.Duration) *Arrangement[T, U] {
+	r.minDeadline = remaining
+	return r
+}
+
+// checkDeadline returns an error if the context has less than the minimum deadline left
+func (r *Arrangement[T, U]) checkDeadline(ctx context.Context) *Error {
+	if r.minDeadline <= 0 {
+		return nil
+	}
+	deadline, ok := ctx.Deadline()
+	if !ok || time.Until(deadline) >= r.minDeadline {
+		return nil
+	}
+	serr := &Error{}
+	serr.SetTimeoutError(&TimeoutError{
+		Stage:   ServeStage,
+		Timeout: r.minDeadline,
+		Err:     ErrDeadlineTooShort,
+	})
+	return serr
+}
+
+// timeoutStage wraps next to run with a context derived with the timeout. A failure once the
+// timeout has passed is returned as a TimeoutError, unless the parent context is already done
+func timeoutStage[T proto.Message, U any](stage Stage, timeout time.Duration, next StageFunc[T, U]) StageFunc[T, U] {
+	if timeout <= 0 {
+		return next
+	}
+	return func(ctx context.Context, message T) (U, error) {
+		sctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		res, err := next(sctx, message)
+		if err != nil && ctx.Err() == nil && errors.Is(sctx.Err(), context.DeadlineExceeded) {
+			return res, NewTimeoutError(stage, timeout)
+		}
+		return res, err
+	}
+}
@@ -0,0 +1,217 @@
+package resdes
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	v1 "github.com/signal426/resdes/test_protos/gen/test_protos/resdes/v1"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+)
+
+// blocking waits for the context to be done and returns its error
+func blocking(ctx context.Context, r *v1.CreateUserRequest) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestTimeouts(t *testing.T) {
+	serve := func(ctx context.Context, r *v1.CreateUserRequest) (*v1.CreateUserResponse, error) {
+		return &v1.CreateUserResponse{User: r.GetUser()}, nil
+	}
+
+	t.Run("it should return a timeout error of the auth stage", func(t *testing.T) {
+		// arrange
+		req := &v1.CreateUserRequest{User: &v1.User{Id: "abc123"}}
+		served := false
+
+		// act
+		_, err := Arrange[*v1.CreateUserRequest, *v1.CreateUserResponse]().
+			WithAuth(blocking).
+			WithServe(func(ctx context.Context, r *v1.CreateUserRequest) (*v1.CreateUserResponse, error) {
+				served = true
+				return nil, nil
+			}).
+			WithStageTimeout(AuthStage, 10*time.Millisecond).
+			Exec(context.Background(), req)
+
+		// assert
+		var terr *TimeoutError
+		assert.ErrorAs(t, err, &terr)
+		assert.Equal(t, AuthStage, err.Stage())
+		assert.Equal(t, 10*time.Millisecond, terr.Timeout)
+		assert.ErrorIs(t, err, ErrAuthTimeout)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.NotErrorIs(t, err, ErrServeTimeout)
+		assert.Nil(t, err.GetAuthError())
+		assert.False(t, served)
+	})
+
+	t.Run("it should return a timeout error of the validate stage", func(t *testing.T) {
+		// arrange
+		req := &v1.CreateUserRequest{User: &v1.User{Id: "abc123"}}
+
+		// act
+		_, err := Arrange[*v1.CreateUserRequest, *v1.CreateUserResponse]().
+			WithValidate(ForMessage[*v1.CreateUserRequest]().
+				CustomValidation(func(ctx context.Context, r *v1.CreateUserRequest, errs *ValidationErrors) error {
+					return blocking(ctx, r)
+				})).
+			WithServe(serve).
+			WithStageTimeout(ValidateStage, 10*time.Millisecond).
+			Exec(context.Background(), req)
+
+		// assert
+		assert.ErrorIs(t, err, ErrValidateTimeout)
+		assert.Equal(t, ValidateStage, err.Stage())
+		assert.Nil(t, err.GetValidationErrors())
+	})
+
+	t.Run("it should return a timeout error of the serve stage", func(t *testing.T) {
+		// arrange
+		req := &v1.CreateUserRequest{User: &v1.User{Id: "abc123"}}
+
+		// act
+		_, err := Arrange[*v1.CreateUserRequest, *v1.CreateUserResponse]().
+			WithAuth(func(ctx context.Context, r *v1.CreateUserRequest) error {
+				return nil
+			}).
+			WithServe(func(ctx context.Context, r *v1.CreateUserRequest) (*v1.CreateUserResponse, error) {
+				return nil, blocking(ctx, r)
+			}).
+			WithStageTimeout(AuthStage, time.Second).
+			WithStageTimeout(ServeStage, 10*time.Millisecond).
+			Exec(context.Background(), req)
+
+		// assert
+		assert.ErrorIs(t, err, ErrServeTimeout)
+		assert.Equal(t, ServeStage, err.Stage())
+		assert.EqualError(t, err, "serve stage timed out after 10ms")
+	})
+
+	t.Run("it should keep the error of a stage that fails before its timeout", func(t *testing.T) {
+		// arrange
+		req := &v1.CreateUserRequest{User: &v1.User{Id: "abc123"}}
+
+		// act
+		_, err := Arrange[*v1.CreateUserRequest, *v1.CreateUserResponse]().
+			WithAuth(func(ctx context.Context, r *v1.CreateUserRequest) error {
+				return ErrPermissionDenied
+			}).
+			WithServe(serve).
+			WithStageTimeout(AuthStage, time.Second).
+			Exec(context.Background(), req)
+
+		// assert
+		assert.NotNil(t, err.GetAuthError())
+		assert.Nil(t, err.GetTimeoutError())
+	})
+
+	t.Run("it should not report a stage timeout when the request context is done", func(t *testing.T) {
+		// arrange
+		req := &v1.CreateUserRequest{User: &v1.User{Id: "abc123"}}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		// act
+		_, err := Arrange[*v1.CreateUserRequest, *v1.CreateUserResponse]().
+			WithAuth(blocking).
+			WithServe(serve).
+			WithStageTimeout(AuthStage, 10*time.Millisecond).
+			Exec(ctx, req)
+
+		// assert
+		assert.NotNil(t, err.GetAuthError())
+		assert.Nil(t, err.GetTimeoutError())
+	})
+
+	t.Run("it should pass the stage deadline to stage middleware", func(t *testing.T) {
+		// arrange
+		req := &v1.CreateUserRequest{User: &v1.User{Id: "abc123"}}
+		var hasDeadline bool
+
+		// act
+		_, err := Arrange[*v1.CreateUserRequest, *v1.CreateUserResponse]().
+			WithServe(serve).
+			WithStageTimeout(ServeStage, time.Second).
+			UseStage(func(ctx context.Context, stage Stage, msg *v1.CreateUserRequest, next StageFunc[*v1.CreateUserRequest, *v1.CreateUserResponse]) (*v1.CreateUserResponse, error) {
+				_, hasDeadline = ctx.Deadline()
+				return next(ctx, msg)
+			}, ServeStage).
+			Exec(context.Background(), req)
+
+		// assert
+		assert.Nil(t, err)
+		assert.True(t, hasDeadline)
+	})
+
+	t.Run("it should reject a request without enough time left to serve", func(t *testing.T) {
+		// arrange
+		req := &v1.CreateUserRequest{User: &v1.User{Id: "abc123"}}
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		served := false
+
+		// act
+		_, err := Arrange[*v1.CreateUserRequest, *v1.CreateUserResponse]().
+			WithServe(func(ctx context.Context, r *v1.CreateUserRequest) (*v1.CreateUserResponse, error) {
+				served = true
+				return nil, nil
+			}).
+			WithMinDeadline(time.Second).
+			Exec(ctx, req)
+
+		// assert
+		assert.ErrorIs(t, err, ErrDeadlineTooShort)
+		assert.Equal(t, ServeStage, err.Stage())
+		assert.False(t, served)
+	})
+
+	t.Run("it should serve a request with enough time left or no deadline", func(t *testing.T) {
+		// arrange
+		req := &v1.CreateUserRequest{User: &v1.User{Id: "abc123"}}
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		arrangement := Arrange[*v1.CreateUserRequest, *v1.CreateUserResponse]().
+			WithServe(serve).
+			WithMinDeadline(time.Second)
+
+		// act
+		_, withDeadline := arrangement.Exec(ctx, req)
+		_, withoutDeadline := arrangement.Exec(context.Background(), req)
+
+		// assert
+		assert.Nil(t, withDeadline)
+		assert.Nil(t, withoutDeadline)
+	})
+
+	t.Run("it should convert a timeout error to a deadline exceeded status and back", func(t *testing.T) {
+		// arrange
+		serr := &Error{}
+		serr.SetTimeoutError(&TimeoutError{Stage: ServeStage, Timeout: time.Second, Err: ErrDeadlineTooShort})
+		auth := &Error{}
+		auth.SetTimeoutError(NewTimeoutError(AuthStage, 50*time.Millisecond))
+
+		// act
+		s := serr.ToGrpcStatus()
+		back := FromGrpcStatus(s)
+		authBack := FromError(auth.ToGrpcStatus().Err())
+
+		// assert
+		assert.Equal(t, codes.DeadlineExceeded, s.Code())
+		assert.Equal(t, ServeStage, back.Stage())
+		assert.ErrorIs(t, back, ErrDeadlineTooShort)
+		assert.Equal(t, time.Second, back.GetTimeoutError().Timeout)
+		assert.Equal(t, AuthStage, authBack.Stage())
+		assert.True(t, errors.Is(authBack, ErrAuthTimeout))
+		assert.Equal(t, 50*time.Millisecond, authBack.GetTimeoutError().Timeout)
+	})
+
+	t.Run("it should panic on a timeout of no stage", func(t *testing.T) {
+		assert.Panics(t, func() {
+			Arrange[*v1.CreateUserRequest, *v1.CreateUserResponse]().WithStageTimeout(NoStage, time.Second)
+		})
+	})
+}
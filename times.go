@@ -0,0 +1,187 @@
+package resdes
+
+import (
+	"reflect"
+	"time"
+
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// WithClock sets the clock used by the timestamp assertions relative to now. Defaults to time.Now
+func (s *DefaultMessageValidator[T]) WithClock(now func() time.Time) *DefaultMessageValidator[T] {
+	s.clock = now
+	return s
+}
+
+// AssertTimestampValid assert that the supplied timestamp is within the valid range of a google.protobuf.Timestamp
+func (s *DefaultMessageValidator[T]) AssertTimestampValid(path string, value *timestamppb.Timestamp) *DefaultMessageValidator[T] {
+	return s.timeField(path, value, TimestampValid, Always, nil, "")
+}
+
+// AssertTimestampInPast assert that the supplied timestamp is before now
+func (s *DefaultMessageValidator[T]) AssertTimestampInPast(path string, value *timestamppb.Timestamp) *DefaultMessageValidator[T] {
+	return s.timeField(path, value, TimestampPast, Always, nil, "")
+}
+
+// AssertTimestampInFuture assert that the supplied timestamp is after now
+func (s *DefaultMessageValidator[T]) AssertTimestampInFuture(path string, value *timestamppb.Timestamp) *DefaultMessageValidator[T] {
+	return s.timeField(path, value, TimestampFuture, Always, nil, "")
+}
+
+// AssertTimestampWithin assert that the supplied timestamp is no further than the window from now, in either direction
+func (s *DefaultMessageValidator[T]) AssertTimestampWithin(path string, value *timestamppb.Timestamp, window time.Duration) *DefaultMessageValidator[T] {
+	return s.timeField(path, value, TimestampWithin, Always, window, "")
+}
+
+// AssertTimestampBefore assert that the supplied timestamp is before the timestamp of the other field. Passes if the other field is not set
+func (s *DefaultMessageValidator[T]) AssertTimestampBefore(path string, value *timestamppb.Timestamp, otherPath string, other *timestamppb.Timestamp) *DefaultMessageValidator[T] {
+	return s.timeField(path, value, TimestampBefore, Always, other, otherPath)
+}
+
+// AssertTimestampAfter assert that the supplied timestamp is after the timestamp of the other field. Passes if the other field is not set
+func (s *DefaultMessageValidator[T]) AssertTimestampAfter(path string, value *timestamppb.Timestamp, otherPath string, other *timestamppb.Timestamp) *DefaultMessageValidator[T] {
+	return s.timeField(path, value, TimestampAfter, Always, other, otherPath)
+}
+
+// AssertDurationMin assert that the supplied duration is at least min
+func (s *DefaultMessageValidator[T]) AssertDurationMin(path string, value *durationpb.Duration, min time.Duration) *DefaultMessageValidator[T] {
+	return s.timeField(path, value, DurationMin, Always, min, "")
+}
+
+// AssertDurationMax assert that the supplied duration is at most max
+func (s *DefaultMessageValidator[T]) AssertDurationMax(path string, value *durationpb.Duration, max time.Duration) *DefaultMessageValidator[T] {
+	return s.timeField(path, value, DurationMax, Always, max, "")
+}
+
+// AssertTimestampValidWhenInMask same as AssertTimestampValid, but only executes if the supplied path is in the field mask
+func (s *DefaultMessageValidator[T]) AssertTimestampValidWhenInMask(path string, value *timestamppb.Timestamp) *DefaultMessageValidator[T] {
+	return s.timeField(path, value, TimestampValid, InMask, nil, "")
+}
+
+// AssertTimestampInPastWhenInMask same as AssertTimestampInPast, but only executes if the supplied path is in the field mask
+func (s *DefaultMessageValidator[T]) AssertTimestampInPastWhenInMask(path string, value *timestamppb.Timestamp) *DefaultMessageValidator[T] {
+	return s.timeField(path, value, TimestampPast, InMask, nil, "")
+}
+
+// AssertTimestampInFutureWhenInMask same as AssertTimestampInFuture, but only executes if the supplied path is in the field mask
+func (s *DefaultMessageValidator[T]) AssertTimestampInFutureWhenInMask(path string, value *timestamppb.Timestamp) *DefaultMessageValidator[T] {
+	return s.timeField(path, value, TimestampFuture, InMask, nil, "")
+}
+
+// AssertTimestampWithinWhenInMask same as AssertTimestampWithin, but only executes if the supplied path is in the field mask
+func (s *DefaultMessageValidator[T]) AssertTimestampWithinWhenInMask(path string, value *timestamppb.Timestamp, window time.Duration) *DefaultMessageValidator[T] {
+	return s.timeField(path, value, TimestampWithin, InMask, window, "")
+}
+
+// AssertTimestampBeforeWhenInMask same as AssertTimestampBefore, but only executes if the supplied path is in the field mask
+func (s *DefaultMessageValidator[T]) AssertTimestampBeforeWhenInMask(path string, value *timestamppb.Timestamp, otherPath string, other *timestamppb.Timestamp) *DefaultMessageValidator[T] {
+	return s.timeField(path, value, TimestampBefore, InMask, other, otherPath)
+}
+
+// AssertTimestampAfterWhenInMask same as AssertTimestampAfter, but only executes if the supplied path is in the field mask
+func (s *DefaultMessageValidator[T]) AssertTimestampAfterWhenInMask(path string, value *timestamppb.Timestamp, otherPath string, other *timestamppb.Timestamp) *DefaultMessageValidator[T] {
+	return s.timeField(path, value, TimestampAfter, InMask, other, otherPath)
+}
+
+// AssertDurationMinWhenInMask same as AssertDurationMin, but only executes if the supplied path is in the field mask
+func (s *DefaultMessageValidator[T]) AssertDurationMinWhenInMask(path string, value *durationpb.Duration, min time.Duration) *DefaultMessageValidator[T] {
+	return s.timeField(path, value, DurationMin, InMask, min, "")
+}
+
+// AssertDurationMaxWhenInMask same as AssertDurationMax, but only executes if the supplied path is in the field mask
+func (s *DefaultMessageValidator[T]) AssertDurationMaxWhenInMask(path string, value *durationpb.Duration, max time.Duration) *DefaultMessageValidator[T] {
+	return s.timeField(path, value, DurationMax, InMask, max, "")
+}
+
+func (s *DefaultMessageValidator[T]) timeField(path string, value any, policy Policy, condition Condition, cmpTo any, other string) *DefaultMessageValidator[T] {
+	f := NewField(path, value, policy, condition, cmpTo, s.paths)
+	f.now = s.now
+	f.trigger = other
+	s.rules = append(s.rules, f)
+	return s
+}
+
+func (s *DefaultMessageValidator[T]) now() time.Time {
+	if s.clock != nil {
+		return s.clock()
+	}
+	return time.Now()
+}
+
+// checkTime checks a timestamp or duration. Unset values pass, use Require
+// or AssertNonZero to require them. Values of any other type are not comparable
+func (f Field) checkTime() error {
+	switch v := f.value.(type) {
+	case nil:
+		return nil
+	case *durationpb.Duration:
+		if f.policy == DurationMin || f.policy == DurationMax {
+			return f.checkDuration(v)
+		}
+	case *timestamppb.Timestamp:
+		if f.policy != DurationMin && f.policy != DurationMax {
+			return f.checkTimestamp(v)
+		}
+	}
+	return newFieldsNotComparableErr(f.path, reflect.TypeOf(f.value), reflect.TypeOf(f.cmpTo))
+}
+
+func (f Field) checkTimestamp(ts *timestamppb.Timestamp) error {
+	if ts == nil {
+		return nil
+	}
+	if ts.CheckValid() != nil {
+		return newFieldTimeErr(f.path, ts, ErrFieldInvalidTimestamp)
+	}
+	t := ts.AsTime()
+	now := time.Now
+	if f.now != nil {
+		now = f.now
+	}
+	switch f.policy {
+	case TimestampPast:
+		if !t.Before(now()) {
+			return newFieldTimeErr(f.path, t, ErrFieldTimestampNotInPast)
+		}
+	case TimestampFuture:
+		if !t.After(now()) {
+			return newFieldTimeErr(f.path, t, ErrFieldTimestampNotInFuture)
+		}
+	case TimestampWithin:
+		window, _ := f.cmpTo.(time.Duration)
+		if d := t.Sub(now()); d > window || d < -window {
+			return newFieldTimeBoundErr(f.path, t, window, ErrFieldTimestampOutsideWindow)
+		}
+	case TimestampBefore, TimestampAfter:
+		other, _ := f.cmpTo.(*timestamppb.Timestamp)
+		if other == nil || other.CheckValid() != nil {
+			return nil
+		}
+		ot := other.AsTime()
+		if f.policy == TimestampBefore && !t.Before(ot) {
+			return newFieldTimeOtherErr(f.path, t, f.trigger, ot, ErrFieldTimestampNotBefore)
+		}
+		if f.policy == TimestampAfter && !t.After(ot) {
+			return newFieldTimeOtherErr(f.path, t, f.trigger, ot, ErrFieldTimestampNotAfter)
+		}
+	}
+	return nil
+}
+
+func (f Field) checkDuration(d *durationpb.Duration) error {
+	if d == nil {
+		return nil
+	}
+	if d.CheckValid() != nil {
+		return newFieldTimeErr(f.path, d, ErrFieldInvalidDuration)
+	}
+	bound, _ := f.cmpTo.(time.Duration)
+	switch {
+	case f.policy == DurationMin && d.AsDuration() < bound:
+		return newFieldTimeBoundErr(f.path, d.AsDuration(), bound, ErrFieldDurationTooShort)
+	case f.policy == DurationMax && d.AsDuration() > bound:
+		return newFieldTimeBoundErr(f.path, d.AsDuration(), bound, ErrFieldDurationTooLong)
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+package resdes
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	v1 "github.com/signal426/resdes/test_protos/gen/test_protos/resdes/v1"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestTimeValidations(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+
+	t.Run("it should compare timestamps to the clock", func(t *testing.T) {
+		tests := []struct {
+			name   string
+			assert func(*DefaultMessageValidator[*v1.User]) *DefaultMessageValidator[*v1.User]
+			err    error
+		}{
+			{
+				name: "valid",
+				assert: func(v *DefaultMessageValidator[*v1.User]) *DefaultMessageValidator[*v1.User] {
+					return v.AssertTimestampValid("ts", &timestamppb.Timestamp{Seconds: -62135596801})
+				},
+				err: ErrFieldInvalidTimestamp,
+			},
+			{
+				name: "past",
+				assert: func(v *DefaultMessageValidator[*v1.User]) *DefaultMessageValidator[*v1.User] {
+					return v.AssertTimestampInPast("ts", timestamppb.New(now))
+				},
+				err: ErrFieldTimestampNotInPast,
+			},
+			{
+				name: "future",
+				assert: func(v *DefaultMessageValidator[*v1.User]) *DefaultMessageValidator[*v1.User] {
+					return v.AssertTimestampInFuture("ts", timestamppb.New(now.Add(-time.Second)))
+				},
+				err: ErrFieldTimestampNotInFuture,
+			},
+			{
+				name: "within",
+				assert: func(v *DefaultMessageValidator[*v1.User]) *DefaultMessageValidator[*v1.User] {
+					return v.AssertTimestampWithin("ts", timestamppb.New(now.Add(-2*time.Minute)), time.Minute)
+				},
+				err: ErrFieldTimestampOutsideWindow,
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// act
+				err := tt.assert(ForMessage[*v1.User]().WithClock(clock)).Exec(context.Background(), &v1.User{})
+
+				// assert
+				assert.ErrorIs(t, err, tt.err)
+			})
+		}
+	})
+
+	t.Run("it should pass timestamps relative to the clock", func(t *testing.T) {
+		// act
+		err := ForMessage[*v1.User]().
+			AssertTimestampValid("a", timestamppb.New(now)).
+			AssertTimestampInPast("b", timestamppb.New(now.Add(-time.Nanosecond))).
+			AssertTimestampInFuture("c", timestamppb.New(now.Add(time.Hour))).
+			AssertTimestampWithin("d", timestamppb.New(now.Add(time.Minute)), time.Minute).
+			AssertTimestampInPast("unset", nil).
+			WithClock(clock).
+			Exec(context.Background(), &v1.User{})
+
+		// assert
+		assert.Nil(t, err)
+	})
+
+	t.Run("it should compare a timestamp to another field", func(t *testing.T) {
+		// arrange
+		start := timestamppb.New(now)
+		end := timestamppb.New(now.Add(-time.Hour))
+		expected := &ValidationErrors{
+			FieldErrors: []*FieldError{
+				{
+					Path:   "end_time",
+					Policy: TimestampAfter,
+					Err:    newFieldTimeOtherErr("end_time", end.AsTime(), "start_time", start.AsTime(), ErrFieldTimestampNotAfter),
+				},
+			},
+		}
+
+		// act
+		err := ForMessage[*v1.User]().
+			AssertTimestampBefore("start_time", start, "end_time", end).
+			AssertTimestampAfter("end_time", end, "start_time", start).
+			AssertTimestampAfter("start_time", start, "unset_time", nil).
+			Exec(context.Background(), &v1.User{})
+
+		// assert
+		assert.ErrorIs(t, err, ErrFieldTimestampNotBefore)
+		assert.ErrorIs(t, err, ErrFieldTimestampNotAfter)
+		assert.Equal(t, []string{"start_time", "end_time"}, err.Paths())
+		assert.Equal(t, "start_time", err.FieldErrors[1].Trigger)
+		assert.Equal(t, expected.FieldErrors[0].Error(), err.FieldErrors[1].Error())
+	})
+
+	t.Run("it should bound durations", func(t *testing.T) {
+		// act
+		err := ForMessage[*v1.User]().
+			AssertDurationMin("a", durationpb.New(time.Second), time.Minute).
+			AssertDurationMax("b", durationpb.New(time.Hour), time.Minute).
+			AssertDurationMin("c", &durationpb.Duration{Seconds: 1, Nanos: -1}, 0).
+			AssertDurationMin("d", durationpb.New(time.Minute), time.Minute).
+			AssertDurationMax("e", nil, time.Minute).
+			Exec(context.Background(), &v1.User{})
+
+		// assert
+		assert.ErrorIs(t, err, ErrFieldDurationTooShort)
+		assert.ErrorIs(t, err, ErrFieldDurationTooLong)
+		assert.ErrorIs(t, err, ErrFieldInvalidDuration)
+		assert.Equal(t, []string{"a", "b", "c"}, err.Paths())
+		assert.Equal(t, time.Minute, err.FieldErrors[0].Expected)
+	})
+
+	t.Run("it should not compare values that are not timestamps or durations", func(t *testing.T) {
+		// arrange
+		schema := NewSchema[*v1.User]()
+		Extract(schema, "created", func(u *v1.User) time.Time { return now }).Assert(TimestampPast, nil)
+		Extract(schema, "ttl", func(u *v1.User) *timestamppb.Timestamp { return timestamppb.New(now) }).Assert(DurationMax, time.Minute)
+
+		// act
+		err := schema.Exec(context.Background(), &v1.User{})
+
+		// assert
+		assert.ErrorIs(t, err, ErrFieldComparisonFailedNotComparable)
+		assert.Equal(t, []string{"created", "ttl"}, err.Paths())
+	})
+
+	t.Run("it should only check in-mask timestamps", func(t *testing.T) {
+		// act
+		err := ForMessage[*v1.User]("a").
+			AssertTimestampInFutureWhenInMask("a", timestamppb.New(now)).
+			AssertTimestampInFutureWhenInMask("b", timestamppb.New(now)).
+			WithClock(clock).
+			Exec(context.Background(), &v1.User{})
+
+		// assert
+		assert.Equal(t, []string{"a"}, err.Paths())
+	})
+}